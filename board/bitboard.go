@@ -0,0 +1,222 @@
+package board
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// A Bitboard has one bit per square: bit 0 is A1, bit 7 is H1, bit 56 is A8 and bit 63 is H8.
+type Bitboard uint64
+
+const (
+	FILE_A Bitboard = 0x0101010101010101
+	FILE_H Bitboard = 0x8080808080808080
+	RANK_1 Bitboard = 0x00000000000000FF
+	RANK_8 Bitboard = 0xFF00000000000000
+
+	LIGHT_SQUARES Bitboard = 0x55AA55AA55AA55AA
+)
+
+var KNIGHT_ATTACKS [64]Bitboard
+var KING_ATTACKS [64]Bitboard
+var PAWN_ATTACKS [2][64]Bitboard   // squares a pawn of each player on each square attacks
+
+var BETWEEN [64][64]Bitboard   // squares strictly between two squares on a shared rank, file or diagonal
+var LINE [64][64]Bitboard      // the whole rank, file or diagonal two squares share, edge to edge
+
+var rookDirections = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+var bishopDirections = [][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+
+// Sliding attacks are looked up with magic bitboards (https://www.chessprogramming.org/Magic_Bitboards):
+// the occupied squares along the piece's lines, times a magic number, shifted down, index a table
+// holding the attacked squares for that occupancy.
+type magic struct{
+	mask Bitboard         // squares whose occupancy can block the piece, board edges excluded
+	magic Bitboard
+	shift uint
+	attacks []Bitboard
+}
+
+var rookMagics [64]magic
+var bishopMagics [64]magic
+
+// Square is the index of the square on file ('A' to 'H') and rank (1 to 8), as used by Bitboard and Board.SQ.
+func Square(file int, rank int) int {
+	return (rank - 1) * 8 + (file - 'A')
+}
+
+// FileOf is the file, 'A' to 'H', of square index sq.
+func FileOf(sq int) int {
+	return sq % 8 + 'A'
+}
+
+// RankOf is the rank, 1 to 8, of square index sq.
+func RankOf(sq int) int {
+	return sq / 8 + 1
+}
+
+// Bit is the Bitboard holding only square sq.
+func Bit(sq int) Bitboard {
+	return Bitboard(1) << uint(sq)
+}
+
+// PopCount is the number of squares in b.
+func PopCount(b Bitboard) int {
+	return bits.OnesCount64(uint64(b))
+}
+
+// LSB is the lowest square in b, or 64 if b is empty.
+func LSB(b Bitboard) int {
+	return bits.TrailingZeros64(uint64(b))
+}
+
+// PopLSB removes the lowest square from b and returns it.
+func PopLSB(b *Bitboard) int {
+	sq := LSB(*b)
+	*b &= *b - 1
+	return sq
+}
+
+// RookAttacks is the squares a rook on sq attacks, up to and including the first piece in occupied on each line.
+func RookAttacks(sq int, occupied Bitboard) Bitboard {
+	m := &rookMagics[sq]
+	return m.attacks[((occupied & m.mask) * m.magic) >> m.shift]
+}
+
+// BishopAttacks is the squares a bishop on sq attacks, up to and including the first piece in occupied on each line.
+func BishopAttacks(sq int, occupied Bitboard) Bitboard {
+	m := &bishopMagics[sq]
+	return m.attacks[((occupied & m.mask) * m.magic) >> m.shift]
+}
+
+// AttacksFrom is the squares a knight, bishop, rook, queen or king on sq attacks. Pawns depend on colour;
+// see PAWN_ATTACKS.
+func AttacksFrom(kind Piece, sq int, occupied Bitboard) Bitboard {
+	switch kind {
+	case KNIGHT:
+		return KNIGHT_ATTACKS[sq]
+	case BISHOP:
+		return BishopAttacks(sq, occupied)
+	case ROOK:
+		return RookAttacks(sq, occupied)
+	case QUEEN:
+		return BishopAttacks(sq, occupied) | RookAttacks(sq, occupied)
+	case KING:
+		return KING_ATTACKS[sq]
+	}
+	return 0
+}
+
+func stepAttacks(sq int, steps [][2]int) Bitboard {
+	attacks := Bitboard(0)
+	for _, step := range steps {
+		f, r := sq % 8 + step[0], sq / 8 + step[1]
+		if (f >= 0) && (f < 8) && (r >= 0) && (r < 8) {
+			attacks |= Bit(r * 8 + f)
+		}
+	}
+	return attacks
+}
+
+func slidingAttacks(sq int, occupied Bitboard, directions [][2]int) Bitboard {
+	// The slow way, walking each line until it runs into a piece. Only used to fill the magic tables.
+	attacks := Bitboard(0)
+	for _, d := range directions {
+		f, r := sq % 8 + d[0], sq / 8 + d[1]
+		for (f >= 0) && (f < 8) && (r >= 0) && (r < 8) {
+			attacks |= Bit(r * 8 + f)
+			if occupied & Bit(r * 8 + f) != 0 {
+				break
+			}
+			f, r = f + d[0], r + d[1]
+		}
+	}
+	return attacks
+}
+
+// Magic numbers for each square, found once by the usual random search for sparse 64-bit numbers that map
+// every blocker pattern to a table entry without destructive collisions.
+var rookMagicNumbers = [64]Bitboard{	0x0A80004000801220, 0x8040004010002008, 0x2080200010008008, 0x1100100008210004,
+	0xC200209084020008, 0x2100010004000208, 0x0400081000822421, 0x0200010422048844,
+	0x0800800080400024, 0x0001402000401000, 0x3000801000802001, 0x4400800800100083,
+	0x0904802402480080, 0x4040800400020080, 0x0018808042000100, 0x4040800080004100,
+	0x0040048001458024, 0x00A0004000205000, 0x3100808010002000, 0x4825010010000820,
+	0x5004808008000401, 0x2024818004000A00, 0x0005808002000100, 0x2100060004806104,
+	0x0080400880008421, 0x4062220600410280, 0x010A004A00108022, 0x0000100080080080,
+	0x0021000500080010, 0x0044000202001008, 0x0000100400080102, 0xC020128200040545,
+	0x0080002000400040, 0x0000804000802004, 0x0000120022004080, 0x010A386103001001,
+	0x9010080080800400, 0x8440020080800400, 0x0004228824001001, 0x000000490A000084,
+	0x0080002000504000, 0x200020005000C000, 0x0012088020420010, 0x0010010080080800,
+	0x0085001008010004, 0x0002000204008080, 0x0040413002040008, 0x0000304081020004,
+	0x0080204000800080, 0x3008804000290100, 0x1010100080200080, 0x2008100208028080,
+	0x5000850800910100, 0x8402019004680200, 0x0120911028020400, 0x0000008044010200,
+	0x0020850200244012, 0x0020850200244012, 0x0000102001040841, 0x140900040A100021,
+	0x000200282410A102, 0x000200282410A102, 0x000200282410A102, 0x4048240043802106,
+}
+var bishopMagicNumbers = [64]Bitboard{	0x40106000A1160020, 0x0020010250810120, 0x2010010220280081, 0x002806004050C040,
+	0x0002021018000000, 0x2001112010000400, 0x0881010120218080, 0x1030820110010500,
+	0x0000120222042400, 0x2000020404040044, 0x8000480094208000, 0x0003422A02000001,
+	0x000A220210100040, 0x8004820202226000, 0x0018234854100800, 0x0100004042101040,
+	0x0004001004082820, 0x0010000810010048, 0x1014004208081300, 0x2080818802044202,
+	0x0040880C00A00100, 0x0080400200522010, 0x0001000188180B04, 0x0080249202020204,
+	0x1004400004100410, 0x00013100A0022206, 0x2148500001040080, 0x4241080011004300,
+	0x4020848004002000, 0x10101380D1004100, 0x0008004422020284, 0x01010A1041008080,
+	0x0808080400082121, 0x0808080400082121, 0x0091128200100C00, 0x0202200802010104,
+	0x8C0A020200440085, 0x01A0008080B10040, 0x0889520080122800, 0x100902022202010A,
+	0x04081A0816002000, 0x0000681208005000, 0x8170840041008802, 0x0A00004200810805,
+	0x0830404408210100, 0x2602208106006102, 0x1048300680802628, 0x2602208106006102,
+	0x0602010120110040, 0x0941010801043000, 0x000040440A210428, 0x0008240020880021,
+	0x0400002012048200, 0x00AC102001210220, 0x0220021002009900, 0x84440C080A013080,
+	0x0001008044200440, 0x0004C04410841000, 0x2000500104011130, 0x1A0C010011C20229,
+	0x0044800112202200, 0x0434804908100424, 0x0300404822C08200, 0x48081010008A2A80,
+}
+
+func initMagics(table *[64]magic, directions [][2]int, numbers *[64]Bitboard) {
+	for sq := 0; sq < 64; sq++ {
+		m := &table[sq]
+		edges := ((RANK_1 | RANK_8) &^ (RANK_1 << uint(8 * (sq / 8)))) | ((FILE_A | FILE_H) &^ (FILE_A << uint(sq % 8)))
+		m.mask = slidingAttacks(sq, 0, directions) &^ edges
+		m.magic = numbers[sq]
+		m.shift = uint(64 - PopCount(m.mask))
+		m.attacks = make([]Bitboard, 1 << uint(PopCount(m.mask)))
+
+		filled := make([]bool, len(m.attacks))
+		subset := Bitboard(0)
+		for {
+			// walk every subset of the mask (the "Carry-Rippler" trick)
+			index := (subset * m.magic) >> m.shift
+			attacks := slidingAttacks(sq, subset, directions)
+			if filled[index] && (m.attacks[index] != attacks) {
+				panic(fmt.Sprintf("Magic number for square %s collides.", SquareName(FileOf(sq), RankOf(sq))))
+			}
+			filled[index] = true
+			m.attacks[index] = attacks
+			subset = (subset - m.mask) & m.mask
+			if subset == 0 {
+				break
+			}
+		}
+	}
+}
+
+func init() {
+	for sq := 0; sq < 64; sq++ {
+		KNIGHT_ATTACKS[sq] = stepAttacks(sq, [][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}})
+		KING_ATTACKS[sq] = stepAttacks(sq, [][2]int{{1, 1}, {1, 0}, {1, -1}, {0, 1}, {0, -1}, {-1, 1}, {-1, 0}, {-1, -1}})
+		PAWN_ATTACKS[0][sq] = stepAttacks(sq, [][2]int{{1, 1}, {-1, 1}})
+		PAWN_ATTACKS[1][sq] = stepAttacks(sq, [][2]int{{1, -1}, {-1, -1}})
+	}
+	initMagics(&rookMagics, rookDirections, &rookMagicNumbers)
+	initMagics(&bishopMagics, bishopDirections, &bishopMagicNumbers)
+
+	for a := 0; a < 64; a++ {
+		for b := 0; b < 64; b++ {
+			for _, directions := range [][][2]int{rookDirections, bishopDirections} {
+				if (a != b) && (slidingAttacks(a, 0, directions) & Bit(b) != 0) {
+					LINE[a][b] = (slidingAttacks(a, 0, directions) & slidingAttacks(b, 0, directions)) | Bit(a) | Bit(b)
+					BETWEEN[a][b] = slidingAttacks(a, Bit(b), directions) & slidingAttacks(b, Bit(a), directions)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package board
+
+import (
+	"fmt"
+)
+
+// A Piece is a colour and a piece type packed into a byte; see the FORMAT below.
+type Piece uint8
+
+// A Board is the pieces on the 64 squares, kept both square by square and as bitboards.
+type Board struct{
+	SQ [64]Piece          // piece on each square, indexed as in a Bitboard ([SQ]uares)
+	BB [2][7]Bitboard     // each player's pieces by type; BB[p][0] holds all of p's pieces ([B]it[B]oards)
+	K uint64              // Zobrist key of the piece placement alone; see GameState.Key ([K]ey)
+}
+
+// A Move takes a piece from one square to another. Castling is the king's move; en passant is the
+// pawn's move onto the en passant target square.
+type Move struct{
+	PL int       // which player made the move              ([PL]ayer)
+	SR int       // rank of the piece initially being moved ([S]ource [R]ank)
+	SF int       // file of the piece initially being moved ([S]ource [F]ile)
+	DR int       // rank of the destination square          ([D]estination [R]ank)
+	DF int       // file of the destination square          ([D]estination [F]ile)
+	P Piece      // piece to promote to, if applicable      ([P]romotion Piece)
+}
+
+// A MoveSequence is moves in the order they are played.
+type MoveSequence []Move
+
+var FILES = [...]int {65, 66, 67, 68, 69, 70, 71, 72} // ASCII Values for ABCDEFGH 
+var RANKS = [...]int {1, 2, 3, 4, 5, 6, 7, 8} 
+
+// FORMAT: 
+// [COLOR (1 bit)] 0 0 0 0 [PIECE TYPE (3 bits)]
+
+const (
+	EMPTY_SQUARE Piece = 0b00000000
+
+	WHITE_PAWN Piece   = 0b00000001
+	WHITE_KNIGHT Piece = 0b00000010
+	WHITE_BISHOP Piece = 0b00000011
+	WHITE_ROOK Piece   = 0b00000100
+	WHITE_QUEEN Piece  = 0b00000101
+	WHITE_KING Piece   = 0b00000110
+
+	BLACK_PAWN Piece   = 0b10000001
+	BLACK_KNIGHT Piece = 0b10000010
+	BLACK_BISHOP Piece = 0b10000011
+	BLACK_ROOK Piece   = 0b10000100
+	BLACK_QUEEN Piece  = 0b10000101
+	BLACK_KING Piece   = 0b10000110
+
+	// the two halves of the format, for colour-generic code
+	PAWN Piece      = 0b00000001
+	KNIGHT Piece    = 0b00000010
+	BISHOP Piece    = 0b00000011
+	ROOK Piece      = 0b00000100
+	QUEEN Piece     = 0b00000101
+	KING Piece      = 0b00000110
+	TYPE_MASK Piece = 0b00000111
+	BLACK Piece     = 0b10000000
+)
+
+var PIECE_NAMES = map[Piece]string{
+	EMPTY_SQUARE : "",
+	WHITE_PAWN   : "",
+	WHITE_KNIGHT : "N",
+	WHITE_BISHOP : "B",
+	WHITE_ROOK   : "R",
+	WHITE_QUEEN  : "Q",
+	WHITE_KING   : "K",
+	BLACK_PAWN   : "",
+	BLACK_KNIGHT : "N",
+	BLACK_BISHOP : "B",
+	BLACK_ROOK   : "R",
+	BLACK_QUEEN  : "Q",
+	BLACK_KING   : "K"}
+
+// String gives the piece and destination, e.g. Nf3. It has no position to go on; see GameState.SAN for
+// proper notation.
+func (m Move) String() string {
+	return fmt.Sprintf("%s%c%v", PIECE_NAMES[m.P], rune(m.DF), m.DR)
+}
+
+// Copy returns a copy of h that can be changed without changing h.
+func (h MoveSequence) Copy() MoveSequence {
+	newH := MoveSequence{}
+	for _, move := range h {
+		newH = append(newH, move)
+	}
+	return newH
+}
+
+// At is the piece on (file, rank), or EMPTY_SQUARE if there is none or the square is off the board.
+func (b *Board) At(file int, rank int) Piece {
+	if (file < 'A') || (file > 'H') || (rank < 1) || (rank > 8) {
+		return EMPTY_SQUARE
+	}
+	return b.SQ[Square(file, rank)]
+}
+
+func (b *Board) put(sq int, p Piece) {
+	if p == EMPTY_SQUARE {
+		return
+	}
+	pl := PlayerOf(p)
+	b.SQ[sq] = p
+	b.BB[pl][p & TYPE_MASK] |= Bit(sq)
+	b.BB[pl][0] |= Bit(sq)
+	b.K ^= zobristPiece(p, sq)
+}
+
+func (b *Board) remove(sq int) {
+	p := b.SQ[sq]
+	if p == EMPTY_SQUARE {
+		return
+	}
+	pl := PlayerOf(p)
+	b.SQ[sq] = EMPTY_SQUARE
+	b.BB[pl][p & TYPE_MASK] &^= Bit(sq)
+	b.BB[pl][0] &^= Bit(sq)
+	b.K ^= zobristPiece(p, sq)
+}
+
+// Occupied is every square with a piece on it.
+func (b *Board) Occupied() Bitboard {
+	return b.BB[0][0] | b.BB[1][0]
+}
+
+// IsWhite reports whether p is a white piece.
+func IsWhite(p Piece) bool {
+	return (p < 128) && (p > 0)
+}
+
+// IsBlack reports whether p is a black piece.
+func IsBlack(p Piece) bool {
+	return p > 128
+}
+
+// PlayerOf is 0 for white pieces, 1 for black pieces and -1 for an empty square.
+func PlayerOf(p Piece) int {
+	if IsWhite(p) {
+		return 0
+	} else if IsBlack(p) {
+		return 1
+	}
+	return -1
+}
+
+// Attackers is p's pieces that attack sq, with the pieces in occupied blocking the sliders.
+func Attackers(b *Board, sq int, p int, occupied Bitboard) Bitboard {
+	return (PAWN_ATTACKS[1 - p][sq] & b.BB[p][PAWN]) |
+		(KNIGHT_ATTACKS[sq] & b.BB[p][KNIGHT]) |
+		(KING_ATTACKS[sq] & b.BB[p][KING]) |
+		(BishopAttacks(sq, occupied) & (b.BB[p][BISHOP] | b.BB[p][QUEEN])) |
+		(RookAttacks(sq, occupied) & (b.BB[p][ROOK] | b.BB[p][QUEEN]))
+}
+
+// PinnedPieces is p's pieces that are all that stands between p's king, on square king, and an enemy rook,
+// bishop or queen.
+func PinnedPieces(b *Board, p int, king int, occupied Bitboard) Bitboard {
+	them := 1 - p
+	snipers := (RookAttacks(king, 0) & (b.BB[them][ROOK] | b.BB[them][QUEEN])) |
+		(BishopAttacks(king, 0) & (b.BB[them][BISHOP] | b.BB[them][QUEEN]))
+	pinned := Bitboard(0)
+	for snipers != 0 {
+		blockers := BETWEEN[king][PopLSB(&snipers)] & occupied
+		if (PopCount(blockers) == 1) && (blockers & b.BB[p][0] != 0) {
+			pinned |= blockers
+		}
+	}
+	return pinned
+}
+
+// Threatens reports whether one of p's pieces attacks (file, rank).
+func Threatens(g *GameState, targetFile int, targetRank int, p int) bool {
+	return Attackers(&g.B, Square(targetFile, targetRank), p, g.B.Occupied()) != 0
+}
+
+// CheckForCheck reports whether player p's king is attacked.
+func CheckForCheck(g *GameState, p int) bool {
+	if g.B.BB[p][KING] == 0 {
+		return false
+	}
+	return Attackers(&g.B, LSB(g.B.BB[p][KING]), 1 - p, g.B.Occupied()) != 0
+}
+
+// GenerateLegalMoves is the legal moves of the piece on (file, rank), if it belongs to the player to move.
+func GenerateLegalMoves(g *GameState, file int, rank int) MoveSequence {
+	moves := make(MoveSequence, 0)
+	for _, m := range LegalMoves(g) {
+		if (m.SF == file) && (m.SR == rank) {
+			moves = append(moves, m)
+		}
+	}
+	return moves
+}
+
+// CheckNoLegalMoves reports whether the player to move has no legal moves: checkmate or stalemate.
+func CheckNoLegalMoves(g *GameState) bool {
+	return len(LegalMoves(g)) == 0
+}
+
+// IsEnPassant reports whether m, about to be played in g, is a pawn moving diagonally onto the en passant
+// target square, capturing the pawn that just skipped over it.
+func IsEnPassant(g *GameState, m Move) bool {
+	moved := g.B.At(m.SF, m.SR)
+	return ((moved == WHITE_PAWN) || (moved == BLACK_PAWN)) && (m.SF != m.DF) && (m.DF == g.EF) && (m.DR == g.ER)
+}
+
+// IsCastling reports whether m, moving piece moved, is castling.
+func IsCastling(moved Piece, m Move) bool {
+	return (moved & TYPE_MASK == KING) && ((m.DF - m.SF == 2) || (m.SF - m.DF == 2))
+}
+
+func castlingRookSquares(m Move) (int, int) {
+	// where the rook starts and ends up when the king makes move m
+	if m.DF == 'G' {
+		return Square('H', m.SR), Square('F', m.SR)
+	}
+	return Square('A', m.SR), Square('D', m.SR)
+}
+
+// PlayMove applies m to the position without recording it in the history, which makes it the cheaper
+// choice for searches. The returned entry holds everything TakeBack needs to restore the position exactly.
+func PlayMove(g *GameState, m Move) HistoryEntry {
+	b := &g.B
+	from, to := Square(m.SF, m.SR), Square(m.DF, m.DR)
+	moved := b.SQ[from]
+	e := HistoryEntry{M: m, MP: moved, CP: b.SQ[to], XF: m.DF, XR: m.DR, PCR: g.CR, PEF: g.EF, PER: g.ER, PHC: g.HC}
+	e.M.PL = g.PL
+	if IsEnPassant(g, m) {
+		// the captured pawn sits beside the source square, not on the destination
+		e.XR = m.SR
+		e.CP = b.SQ[Square(m.DF, m.SR)]
+	}
+
+	b.remove(Square(e.XF, e.XR))
+	b.remove(from)
+	b.put(to, m.P)
+	if IsCastling(moved, m) {
+		rookFrom, rookTo := castlingRookSquares(m)
+		b.put(rookTo, b.SQ[rookFrom])
+		b.remove(rookFrom)
+	}
+
+	// Moving the king gives up both castling rights; a rook leaving or being captured on its corner gives up that side.
+	g.CR &^= CASTLING_LOST[from] | CASTLING_LOST[to]
+
+	// A double pawn push leaves the square it skipped over open to en passant for one move.
+	g.EF, g.ER = 0, 0
+	if (moved & TYPE_MASK == PAWN) && ((m.DR - m.SR == 2) || (m.SR - m.DR == 2)) {
+		g.EF, g.ER = m.SF, (m.SR + m.DR) / 2
+	}
+
+	if (moved & TYPE_MASK == PAWN) || (e.CP != EMPTY_SQUARE) {
+		g.HC = 0
+	} else {
+		g.HC += 1
+	}
+	if g.PL == 1 {
+		g.FN += 1
+	}
+	g.PL = 1 - g.PL
+	return e
+}
+
+// TakeBack reverses a move made by PlayMove, given the entry it returned.
+func TakeBack(g *GameState, e HistoryEntry) {
+	b := &g.B
+	m := e.M
+	if IsCastling(e.MP, m) {
+		rookFrom, rookTo := castlingRookSquares(m)
+		b.put(rookFrom, b.SQ[rookTo])
+		b.remove(rookTo)
+	}
+	b.remove(Square(m.DF, m.DR))
+	b.put(Square(m.SF, m.SR), e.MP)
+	b.put(Square(e.XF, e.XR), e.CP)
+
+	g.CR, g.EF, g.ER, g.HC = e.PCR, e.PEF, e.PER, e.PHC
+	g.PL = m.PL
+	if g.PL == 1 {
+		g.FN -= 1
+	}
+}
@@ -0,0 +1,87 @@
+package board
+
+import (
+	"testing"
+)
+
+func mustLoadFEN(t *testing.T, fen string) *GameState {
+	t.Helper()
+	g, err := LoadFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func findMove(g *GameState, sf int, sr int, df int, dr int) (Move, bool) {
+	for _, m := range GenerateLegalMoves(g, sf, sr) {
+		if (m.DF == df) && (m.DR == dr) {
+			return m, true
+		}
+	}
+	return Move{}, false
+}
+
+func TestEnPassant(t *testing.T) {
+	cases := []struct{
+		name string
+		fen string
+		sf, sr, df, dr int
+		legal bool
+		after string
+	}{
+		{"white captures", "rnbqkbnr/ppp1pppp/8/3pP3/8/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 3",
+			'E', 5, 'D', 6, true, "rnbqkbnr/ppp1pppp/3P4/8/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3"},
+		{"black captures", "rnbqkbnr/pppp1ppp/8/8/3Pp3/8/PPP2PPP/RNBQKBNR b KQkq d3 0 3",
+			'E', 4, 'D', 3, true, "rnbqkbnr/pppp1ppp/8/8/8/3p4/PPP2PPP/RNBQKBNR w KQkq - 0 4"},
+		{"capture removes the checking pawn", "8/8/8/2k5/3Pp3/8/8/3K4 b - d3 0 1",
+			'E', 4, 'D', 3, true, "8/8/8/2k5/8/3p4/8/3K4 w - - 0 2"},
+		{"both pawns leave the rank between king and rook", "8/8/8/KPp4r/8/8/8/7k w - c6 0 1",
+			'B', 5, 'C', 6, false, ""},
+		{"king off the rank is not exposed", "8/8/8/1Pp5/8/8/8/5K1k w - c6 0 1",
+			'B', 5, 'C', 6, true, "8/8/2P5/8/8/8/8/5K1k b - - 0 1"},
+		{"target square has expired", "rnbqkbnr/ppp1pppp/8/3pP3/8/8/PPPP1PPP/RNBQKBNR w KQkq - 0 3",
+			'E', 5, 'D', 6, false, ""},
+	}
+	for _, c := range cases {
+		g := mustLoadFEN(t, c.fen)
+		m, ok := findMove(g, c.sf, c.sr, c.df, c.dr)
+		if ok != c.legal {
+			t.Errorf("%s: legal = %v, want %v", c.name, ok, c.legal)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !IsEnPassant(g, m) {
+			t.Errorf("%s: IsEnPassant(%v) = false", c.name, m)
+		}
+		MakeMove(g, m)
+		if g.FEN() != c.after {
+			t.Errorf("%s: after %v got %s, want %s", c.name, m, g.FEN(), c.after)
+		}
+		if !g.H[len(g.H) - 1].IsEnPassant() {
+			t.Errorf("%s: history entry for %v not marked as en passant", c.name, m)
+		}
+		UndoMove(g)
+		if g.FEN() != c.fen {
+			t.Errorf("%s: undo got %s, want %s", c.name, g.FEN(), c.fen)
+		}
+	}
+}
+
+func TestEnPassantOnlyAfterDoublePush(t *testing.T) {
+	g := mustLoadFEN(t, "rnbqkbnr/pppppppp/8/4P3/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 2")
+	MakeMove(g, Move{SF: 'D', SR: 7, DF: 'D', DR: 5, P: BLACK_PAWN})
+	if m, ok := findMove(g, 'E', 5, 'D', 6); !ok || !IsEnPassant(g, m) {
+		t.Fatalf("exd6 e.p. not available after d7-d5 in %s", g.FEN())
+	}
+
+	g = mustLoadFEN(t, "rnbqkbnr/pppppppp/8/4P3/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 2")
+	MakeMove(g, Move{SF: 'D', SR: 7, DF: 'D', DR: 6, P: BLACK_PAWN})
+	MakeMove(g, Move{SF: 'A', SR: 2, DF: 'A', DR: 3, P: WHITE_PAWN})
+	MakeMove(g, Move{SF: 'D', SR: 6, DF: 'D', DR: 5, P: BLACK_PAWN})
+	if _, ok := findMove(g, 'E', 5, 'D', 6); ok {
+		t.Fatalf("exd6 e.p. available after two single steps in %s", g.FEN())
+	}
+}
@@ -0,0 +1,17 @@
+// Package board implements the rules of chess: positions and FEN, legal move generation, making and
+// taking back moves, game results (checkmate, stalemate and the draw rules), SAN and UCI notation, PGN
+// reading and writing, Zobrist keys and perft.
+//
+// A game is a *GameState, from NewGame or LoadFEN. LegalMoves lists the moves for the player to move,
+// ParseMove turns notation into one of them, MakeMove plays it and UndoMove takes it back. Result says
+// when the game is over.
+//
+//	g, _ := board.NewGame()
+//	m, err := board.ParseMove(g, "e4")
+//	if err == nil {
+//		board.MakeMove(g, m)
+//	}
+//	fmt.Println(g.FEN(), board.Result(g))
+//
+// Files are the ASCII values 'A' to 'H' and ranks run 1 to 8; player 0 is white and player 1 black.
+package board
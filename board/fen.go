@@ -0,0 +1,191 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const START_FEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+var FEN_PIECES = map[byte]Piece{
+	'P' : WHITE_PAWN,
+	'N' : WHITE_KNIGHT,
+	'B' : WHITE_BISHOP,
+	'R' : WHITE_ROOK,
+	'Q' : WHITE_QUEEN,
+	'K' : WHITE_KING,
+	'p' : BLACK_PAWN,
+	'n' : BLACK_KNIGHT,
+	'b' : BLACK_BISHOP,
+	'r' : BLACK_ROOK,
+	'q' : BLACK_QUEEN,
+	'k' : BLACK_KING}
+
+var FEN_CASTLING = map[byte]CastlingRights{
+	'K' : WHITE_KINGSIDE,
+	'Q' : WHITE_QUEENSIDE,
+	'k' : BLACK_KINGSIDE,
+	'q' : BLACK_QUEENSIDE}
+
+// FENChar is the letter FEN uses for p.
+func FENChar(p Piece) byte {
+	for c, piece := range FEN_PIECES {
+		if piece == p {
+			return c
+		}
+	}
+	return '?'
+}
+
+// NewGame returns a game at the standard starting position.
+func NewGame() (*GameState, error) {
+	return LoadFEN(START_FEN)
+}
+
+// LoadFEN returns a game starting from the position fen describes, with an error saying what is wrong
+// if it isn't a valid FEN.
+func LoadFEN(fen string) (*GameState, error) {
+	// FORMAT (https://www.chessprogramming.org/Forsyth-Edwards_Notation):
+	// [PLACEMENT] [SIDE TO MOVE] [CASTLING] [EN PASSANT] [HALFMOVE CLOCK] [FULLMOVE NUMBER]
+	// The two clocks may be left off (as in EPD), in which case they default to 0 and 1.
+	fields := strings.Fields(fen)
+	if (len(fields) != 4) && (len(fields) != 6) {
+		return nil, fmt.Errorf("Invalid FEN %q: expected 6 fields, found %d.", fen, len(fields))
+	}
+
+	g := &GameState{FN: 1}
+
+	rows := strings.Split(fields[0], "/")
+	if len(rows) != 8 {
+		return nil, fmt.Errorf("Invalid FEN %q: expected 8 ranks, found %d.", fen, len(rows))
+	}
+	kings := map[Piece]int{}
+	for i, row := range rows {
+		rank := 8 - i
+		file := int('A')
+		for j := 0; j < len(row); j++ {
+			c := row[j]
+			if (c >= '1') && (c <= '8') {
+				file += int(c - '0')
+				continue
+			}
+			p, ok := FEN_PIECES[c]
+			if !ok {
+				return nil, fmt.Errorf("Invalid FEN %q: unknown piece %q.", fen, c)
+			}
+			if file > 'H' {
+				return nil, fmt.Errorf("Invalid FEN %q: rank %d has more than 8 squares.", fen, rank)
+			}
+			if ((p == WHITE_PAWN) || (p == BLACK_PAWN)) && ((rank == 1) || (rank == 8)) {
+				return nil, fmt.Errorf("Invalid FEN %q: pawn on rank %d.", fen, rank)
+			}
+			g.B.put(Square(file, rank), p)
+			kings[p] += 1
+			file += 1
+		}
+		if file != 'H' + 1 {
+			return nil, fmt.Errorf("Invalid FEN %q: rank %d does not have 8 squares.", fen, rank)
+		}
+	}
+	if (kings[WHITE_KING] != 1) || (kings[BLACK_KING] != 1) {
+		return nil, fmt.Errorf("Invalid FEN %q: each side must have exactly one king.", fen)
+	}
+
+	switch fields[1] {
+	case "w":
+		g.PL = 0
+	case "b":
+		g.PL = 1
+	default:
+		return nil, fmt.Errorf("Invalid FEN %q: unknown side to move %q.", fen, fields[1])
+	}
+	if CheckForCheck(g, 1 - g.PL) {
+		// the player to move could take the king, which the rest of the package assumes never happens
+		return nil, fmt.Errorf("Invalid FEN %q: the side not to move is in check.", fen)
+	}
+
+	if fields[2] != "-" {
+		for j := 0; j < len(fields[2]); j++ {
+			right, ok := FEN_CASTLING[fields[2][j]]
+			if !ok || (g.CR & right != 0) {
+				return nil, fmt.Errorf("Invalid FEN %q: bad castling rights %q.", fen, fields[2])
+			}
+			g.CR |= right
+		}
+	}
+
+	if fields[3] != "-" {
+		file, rank, err := ParseSquare(fields[3])
+		if (err != nil) || ((g.PL == 0) && (rank != 6)) || ((g.PL == 1) && (rank != 3)) {
+			return nil, fmt.Errorf("Invalid FEN %q: bad en passant square %q.", fen, fields[3])
+		}
+		g.EF, g.ER = file, rank
+	}
+
+	if len(fields) == 6 {
+		hc, err := strconv.Atoi(fields[4])
+		if (err != nil) || (hc < 0) {
+			return nil, fmt.Errorf("Invalid FEN %q: bad halfmove clock %q.", fen, fields[4])
+		}
+		fn, err := strconv.Atoi(fields[5])
+		if (err != nil) || (fn < 1) {
+			return nil, fmt.Errorf("Invalid FEN %q: bad fullmove number %q.", fen, fields[5])
+		}
+		g.HC, g.FN = hc, fn
+	}
+
+	g.SP = g.FEN()
+	return g, nil
+}
+
+// FEN describes the position in Forsyth-Edwards Notation.
+func (g *GameState) FEN() string {
+	var sb strings.Builder
+	for rank := 8; rank >= 1; rank-- {
+		empty := 0
+		for _, file := range FILES {
+			p := g.B.At(file, rank)
+			if p == EMPTY_SQUARE {
+				empty += 1
+				continue
+			}
+			if empty > 0 {
+				sb.WriteByte(byte('0' + empty))
+				empty = 0
+			}
+			sb.WriteByte(FENChar(p))
+		}
+		if empty > 0 {
+			sb.WriteByte(byte('0' + empty))
+		}
+		if rank > 1 {
+			sb.WriteByte('/')
+		}
+	}
+
+	if g.PL == 0 {
+		sb.WriteString(" w ")
+	} else {
+		sb.WriteString(" b ")
+	}
+
+	if g.CR == NO_CASTLING {
+		sb.WriteString("-")
+	} else {
+		for _, c := range []byte("KQkq") {
+			if g.CR & FEN_CASTLING[c] != 0 {
+				sb.WriteByte(c)
+			}
+		}
+	}
+
+	if g.EF == 0 {
+		sb.WriteString(" -")
+	} else {
+		sb.WriteString(" " + SquareName(g.EF, g.ER))
+	}
+
+	fmt.Fprintf(&sb, " %d %d", g.HC, g.FN)
+	return sb.String()
+}
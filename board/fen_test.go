@@ -0,0 +1,42 @@
+package board
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLoadFENErrors(t *testing.T) {
+	cases := []struct{
+		fen string
+		err string
+	}{
+		{"4k3/8/8/8/8/8/8/4K3 w -", "expected 6 fields, found 3."},
+		{"4k3/8/8/8/8/8/4K3 w - - 0 1", "expected 8 ranks, found 7."},
+		{"4k3/8/8/8/8/8/7/4K3 w - - 0 1", "rank 2 does not have 8 squares."},
+		{"4k3/8/8/8/8/8/8P/4K3 w - - 0 1", "rank 2 has more than 8 squares."},
+		{"4k3/8/8/8/8/8/8/4X3 w - - 0 1", "unknown piece 'X'."},
+		{"8/8/8/8/8/8/8/4K3 w - - 0 1", "each side must have exactly one king."},
+		{"4k3/8/8/8/8/8/8/3KK3 w - - 0 1", "each side must have exactly one king."},
+		{"4k2P/8/8/8/8/8/8/4K3 w - - 0 1", "pawn on rank 8."},
+		{"4k3/8/8/8/8/8/8/p3K3 b - - 0 1", "pawn on rank 1."},
+		{"4k3/8/8/8/8/8/8/4K3 x - - 0 1", `unknown side to move "x".`},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkx - 0 1", `bad castling rights "KQkx".`},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KK - 0 1", `bad castling rights "KK".`},
+		{"4k3/8/8/8/4P3/8/8/4K3 b - e4 0 1", `bad en passant square "e4".`},
+		{"4k3/8/8/8/4P3/8/8/4K3 w - e3 0 1", `bad en passant square "e3".`},
+		{"4k3/8/8/8/8/8/8/4K3 w - - -1 1", `bad halfmove clock "-1".`},
+		{"4k3/8/8/8/8/8/8/4K3 w - - 0 0", `bad fullmove number "0".`},
+		{"4k3/8/8/8/8/8/4Q3/4K3 w - - 0 1", "the side not to move is in check."},
+	}
+	for _, c := range cases {
+		want := fmt.Sprintf("Invalid FEN %q: %s", c.fen, c.err)
+		if _, err := LoadFEN(c.fen); (err == nil) || (err.Error() != want) {
+			t.Errorf("LoadFEN(%q) error = %v, want %s", c.fen, err, want)
+		}
+	}
+
+	// the side to move may be in check, and EPD leaves off the clocks
+	if _, err := LoadFEN("4k3/8/8/8/8/8/4Q3/4K3 b - -"); err != nil {
+		t.Errorf("black in check: %v", err)
+	}
+}
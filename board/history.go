@@ -0,0 +1,64 @@
+package board
+
+import (
+	"errors"
+)
+
+// A HistoryEntry records a move that was played, with what UndoMove needs to take it back.
+type HistoryEntry struct{
+	M Move                // the move that was played; M.PL is the player who played it
+	FEN string            // the position the move produced
+	K uint64              // Zobrist key of the position the move produced
+
+	// Everything needed to unmake the move without a copy of the old board:
+	MP Piece              // piece that moved, before any promotion        ([M]oved [P]iece)
+	CP Piece              // piece that was captured, if any               ([C]aptured [P]iece)
+	XF int                // file the captured piece stood on              (capture ([X]) [F]ile)
+	XR int                // rank the captured piece stood on              (capture ([X]) [R]ank)
+	PCR CastlingRights    // castling rights before the move               ([P]revious [C]astling [R]ights)
+	PEF int               // en passant file before the move               ([P]revious [E]n passant [F]ile)
+	PER int               // en passant rank before the move               ([P]revious [E]n passant [R]ank)
+	PHC int               // halfmove clock before the move                ([P]revious [H]alfmove [C]lock)
+}
+
+// IsEnPassant reports whether the move took a pawn en passant.
+func (e HistoryEntry) IsEnPassant() bool {
+	return (e.CP != EMPTY_SQUARE) && (e.XR != e.M.DR)
+}
+
+// MakeMove plays m and records it in the game's history. m must be legal, as the moves from LegalMoves and
+// ParseMove are; it isn't checked, and an illegal move leaves the game in a broken state.
+func MakeMove(g *GameState, m Move) {
+	e := PlayMove(g, m)
+	e.FEN = g.FEN()
+	e.K = g.Key()
+	g.H = append(g.H, e)
+}
+
+// UndoMove takes back the last recorded move, restoring the exact position it was played from.
+func UndoMove(g *GameState) error {
+	if len(g.H) == 0 {
+		return errors.New("No moves to undo.")
+	}
+	TakeBack(g, g.H[len(g.H) - 1])
+	g.H = g.H[:len(g.H) - 1]
+	return nil
+}
+
+// PlayedMoves is the moves played since the starting position, oldest first.
+func (g *GameState) PlayedMoves() MoveSequence {
+	moves := make(MoveSequence, len(g.H))
+	for i, entry := range g.H {
+		moves[i] = entry.M
+	}
+	return moves
+}
+
+// Positions()[i] is the FEN of the position produced by PlayedMoves()[i].
+func (g *GameState) Positions() []string {
+	fens := make([]string, len(g.H))
+	for i, entry := range g.H {
+		fens[i] = entry.FEN
+	}
+	return fens
+}
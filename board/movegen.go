@@ -0,0 +1,173 @@
+package board
+
+var PROMOTIONS = [...]Piece{KNIGHT, BISHOP, ROOK, QUEEN}
+
+func newMove(p int, from int, to int, piece Piece) Move {
+	return Move{PL: p, SF: FileOf(from), SR: RankOf(from), DF: FileOf(to), DR: RankOf(to), P: piece}
+}
+
+// MoveFilter says which of the legal moves GenerateMoves should return.
+type MoveFilter int
+
+const (
+	ALL_MOVES MoveFilter = iota
+	CAPTURES             // moves that take a piece, en passant included
+	QUIET_MOVES          // everything else: pushes, promotions onto empty squares, castling
+	CHECKS               // moves that leave the opponent in check
+)
+
+// LegalMoves is every legal move for the player to move.
+func LegalMoves(g *GameState) MoveSequence {
+	return GenerateMoves(g, ALL_MOVES)
+}
+
+// GenerateMoves is the legal moves for the player to move that pass filter.
+func GenerateMoves(g *GameState, filter MoveFilter) MoveSequence {
+	return AppendMoves(g, make(MoveSequence, 0, 64), filter)
+}
+
+// AppendMoves appends the legal moves that pass filter to moves, so that callers generating over and over
+// can reuse one slice.
+func AppendMoves(g *GameState, moves MoveSequence, filter MoveFilter) MoveSequence {
+	if filter != CHECKS {
+		return appendLegalMoves(g, moves, filter)
+	}
+	// A check can come from the moved piece, from a piece it uncovers, from the rook when castling or from
+	// the square a captured pawn leaves, so just try each move.
+	start := len(moves)
+	moves = appendLegalMoves(g, moves, ALL_MOVES)
+	checks := moves[:start]
+	for _, m := range moves[start:] {
+		e := PlayMove(g, m)
+		if CheckForCheck(g, g.PL) {
+			checks = append(checks, m)
+		}
+		TakeBack(g, e)
+	}
+	return checks
+}
+
+func appendLegalMoves(g *GameState, moves MoveSequence, filter MoveFilter) MoveSequence {
+	// Rather than trying each move and seeing whether it leaves the king in check, work out up front what
+	// is giving check and which of our pieces are pinned, and only generate moves that respect both.
+	b := &g.B
+	us, them := g.PL, 1 - g.PL
+	color := Piece(0)
+	if us == 1 {
+		color = BLACK
+	}
+	own, enemy := b.BB[us][0], b.BB[them][0]
+	occupied := own | enemy
+	king := LSB(b.BB[us][KING])
+
+	// FILTER: the squares moves may land on (en passant, landing on an empty square, is handled below)
+	allowed := ^own
+	switch filter {
+	case CAPTURES:
+		allowed = enemy
+	case QUIET_MOVES:
+		allowed = ^occupied
+	}
+
+	// CHECKS: against one checker, every other piece must capture it or step in between; against two, only
+	//         the king can move.
+	checkers := Attackers(b, king, them, occupied)
+	checkMask := ^Bitboard(0)
+	if checkers != 0 {
+		checkMask = BETWEEN[king][LSB(checkers)] | checkers
+	}
+
+	// PINS: a piece that alone stands between our king and an enemy slider can only move along that line.
+	pinned := PinnedPieces(b, us, king, occupied)
+
+	// KING: any square it attacks that isn't ours and that no enemy piece would attack once it got there.
+	//       The king itself is lifted off the board for that, or it would hide the squares behind it.
+	for targets := KING_ATTACKS[king] & allowed; targets != 0; {
+		to := PopLSB(&targets)
+		if Attackers(b, to, them, occupied &^ Bit(king)) == 0 {
+			moves = append(moves, newMove(us, king, to, color | KING))
+		}
+	}
+	if PopCount(checkers) > 1 {
+		return moves
+	}
+
+	// CASTLING: the right must remain, the squares between king and rook must be empty, and the king may not
+	//           start in, pass through or land in check.
+	home := 0
+	kingside, queenside := WHITE_KINGSIDE, WHITE_QUEENSIDE
+	if us == 1 {
+		home = 56
+		kingside, queenside = BLACK_KINGSIDE, BLACK_QUEENSIDE
+	}
+	safe := func(sq int) bool {
+		return Attackers(b, sq, them, occupied) == 0
+	}
+	if (filter != CAPTURES) && (checkers == 0) && (g.CR & (kingside | queenside) != 0) && (king == home + 4) {
+		if (g.CR & kingside != 0) && (b.SQ[home + 7] == color | ROOK) &&
+			(occupied & (Bit(home + 5) | Bit(home + 6)) == 0) && safe(home + 5) && safe(home + 6) {
+			moves = append(moves, newMove(us, home + 4, home + 6, color | KING))
+		}
+		if (g.CR & queenside != 0) && (b.SQ[home] == color | ROOK) &&
+			(occupied & (Bit(home + 1) | Bit(home + 2) | Bit(home + 3)) == 0) && safe(home + 3) && safe(home + 2) {
+			moves = append(moves, newMove(us, home + 4, home + 2, color | KING))
+		}
+	}
+
+	// PAWNS: one square forward onto an empty square, two from the starting rank if both are empty, or
+	//        diagonally forward onto an enemy piece. Reaching the last rank promotes.
+	forward, startRank, lastRank := 8, 2, 8
+	if us == 1 {
+		forward, startRank, lastRank = -8, 7, 1
+	}
+	for pawns := b.BB[us][PAWN]; pawns != 0; {
+		from := PopLSB(&pawns)
+		targets := PAWN_ATTACKS[us][from] & enemy
+		if occupied & Bit(from + forward) == 0 {
+			targets |= Bit(from + forward)
+			if (RankOf(from) == startRank) && (occupied & Bit(from + 2 * forward) == 0) {
+				targets |= Bit(from + 2 * forward)
+			}
+		}
+		targets &= checkMask & allowed
+		if pinned & Bit(from) != 0 {
+			targets &= LINE[king][from]
+		}
+		for targets != 0 {
+			to := PopLSB(&targets)
+			if RankOf(to) == lastRank {
+				for _, promotion := range PROMOTIONS {
+					moves = append(moves, newMove(us, from, to, color | promotion))
+				}
+			} else {
+				moves = append(moves, newMove(us, from, to, color | PAWN))
+			}
+		}
+
+		// EN PASSANT: two pawns leave their squares at once, which pins and check masks don't capture (both
+		//             can vanish from the king's rank, say), so look at the board as it would be afterwards.
+		if (filter != QUIET_MOVES) && (g.EF != 0) && (PAWN_ATTACKS[us][from] & Bit(Square(g.EF, g.ER)) != 0) {
+			to := Square(g.EF, g.ER)
+			captured := Square(g.EF, RankOf(from))
+			after := (occupied &^ (Bit(from) | Bit(captured))) | Bit(to)
+			if Attackers(b, king, them, after) &^ Bit(captured) == 0 {
+				moves = append(moves, newMove(us, from, to, color | PAWN))
+			}
+		}
+	}
+
+	// PIECES: any square they attack that isn't held by one of our own pieces.
+	for _, kind := range [...]Piece{KNIGHT, BISHOP, ROOK, QUEEN} {
+		for pieces := b.BB[us][kind]; pieces != 0; {
+			from := PopLSB(&pieces)
+			targets := AttacksFrom(kind, from, occupied) & allowed & checkMask
+			if pinned & Bit(from) != 0 {
+				targets &= LINE[king][from]
+			}
+			for targets != 0 {
+				moves = append(moves, newMove(us, from, PopLSB(&targets), color | kind))
+			}
+		}
+	}
+	return moves
+}
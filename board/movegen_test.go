@@ -0,0 +1,41 @@
+package board
+
+import (
+	"testing"
+)
+
+func TestGenerateMoves(t *testing.T) {
+	// counts for the first ply, from https://www.chessprogramming.org/Perft_Results
+	cases := []struct{
+		fen string
+		all, captures, checks int
+	}{
+		{START_FEN, 20, 0, 0},
+		{"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", 48, 8, 0},
+		{"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", 14, 1, 2},
+		{"r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", 6, 0, 0},
+		{"8/8/1k6/2b5/2pP4/8/5K2/8 b - d3 0 1", 15, 2, 2},
+	}
+	for _, c := range cases {
+		g := mustLoadFEN(t, c.fen)
+		all := GenerateMoves(g, ALL_MOVES)
+		captures := GenerateMoves(g, CAPTURES)
+		quiet := GenerateMoves(g, QUIET_MOVES)
+		checks := GenerateMoves(g, CHECKS)
+		if (len(all) != c.all) || (len(captures) != c.captures) || (len(checks) != c.checks) {
+			t.Errorf("%s: %d moves, %d captures, %d checks; want %d, %d, %d",
+				c.fen, len(all), len(captures), len(checks), c.all, c.captures, c.checks)
+		}
+		if len(captures) + len(quiet) != len(all) {
+			t.Errorf("%s: %d captures and %d quiet moves, but %d moves", c.fen, len(captures), len(quiet), len(all))
+		}
+		for _, m := range quiet {
+			if (g.B.At(m.DF, m.DR) != EMPTY_SQUARE) || IsEnPassant(g, m) {
+				t.Errorf("%s: quiet move %v captures", c.fen, m)
+			}
+		}
+		if g.FEN() != c.fen {
+			t.Errorf("%s: generating checks left the position as %s", c.fen, g.FEN())
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package board
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Perft counts the leaf nodes of the legal move tree depth plies deep (https://www.chessprogramming.org/Perft).
+// At depth 0 or less the only leaf is the position itself.
+func Perft(g *GameState, depth int) int {
+	if depth < 1 {
+		return 1
+	}
+	return countLeaves(g, depth, make([]MoveSequence, depth + 1))
+}
+
+func countLeaves(g *GameState, depth int, buffers []MoveSequence) int {
+	// buffers[d] holds the moves at each depth d still to go, reused from one node to the next
+	if depth == 0 {
+		return 1
+	}
+	buffers[depth] = AppendMoves(g, buffers[depth][:0], ALL_MOVES)
+	if depth == 1 {
+		return len(buffers[depth])
+	}
+	nodes := 0
+	for _, m := range buffers[depth] {
+		e := PlayMove(g, m)
+		nodes += countLeaves(g, depth - 1, buffers)
+		TakeBack(g, e)
+	}
+	return nodes
+}
+
+// Divide is Perft, with the node count below each root move written to w, one per line, for comparing
+// against another move generator to narrow down which move it disagrees on. Below depth 1 there are no root
+// moves to break the count down by, so only the total of 1 is written.
+func Divide(g *GameState, depth int, w io.Writer) int {
+	if depth < 1 {
+		fmt.Fprintf(w, "\nMoves: 0\nNodes: 1\n")
+		return 1
+	}
+	counts := map[string]int{}
+	lines := make([]string, 0)
+	total := 0
+	for _, m := range LegalMoves(g) {
+		name := CoordinateNotation(g, m)
+		e := PlayMove(g, m)
+		counts[name] = Perft(g, depth - 1)
+		TakeBack(g, e)
+		lines = append(lines, name)
+		total += counts[name]
+	}
+	sort.Strings(lines)
+	for _, name := range lines {
+		fmt.Fprintf(w, "%s: %d\n", name, counts[name])
+	}
+	fmt.Fprintf(w, "\nMoves: %d\nNodes: %d\n", len(lines), total)
+	return total
+}
+
+// CoordinateNotation writes m as its two squares, e.g. e2e4, or e7e8q for a promotion; the notation perft
+// tools and UCI use.
+func CoordinateNotation(g *GameState, m Move) string {
+	s := SquareName(m.SF, m.SR) + SquareName(m.DF, m.DR)
+	moved := g.B.At(m.SF, m.SR)
+	if ((moved == WHITE_PAWN) || (moved == BLACK_PAWN)) && (m.P != moved) {
+		s += PIECE_NAMES[m.P]
+	}
+	return strings.ToLower(s)
+}
@@ -0,0 +1,122 @@
+package board
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Published node counts from https://www.chessprogramming.org/Perft_Results, plus a few small positions
+// aimed at castling rules.
+var PERFT_SUITE = []struct{
+	name string
+	fen string
+	nodes []int   // nodes[i] is the perft result at depth i + 1
+}{
+	{"start", START_FEN,
+		[]int{20, 400, 8902, 197281, 4865609}},
+	{"kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+		[]int{48, 2039, 97862, 4085603}},
+	{"position 3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+		[]int{14, 191, 2812, 43238, 674624}},
+	{"position 4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1",
+		[]int{6, 264, 9467, 422333}},
+	{"position 4 mirrored", "r2q1rk1/pP1p2pp/Q4n2/bbp1p3/Np6/1B3NBn/pPPP1PPP/R3K2R b KQ - 0 1",
+		[]int{6, 264, 9467, 422333}},
+	{"position 5", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8",
+		[]int{44, 1486, 62379, 2103487}},
+	{"position 6", "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10",
+		[]int{46, 2079, 89890, 3894594}},
+	{"castling both sides", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1",
+		[]int{26, 568, 13744, 314346}},
+	{"castling one side", "4k3/8/8/8/8/8/8/4K2R w K - 0 1",
+		[]int{15, 66, 1197, 7059}},
+	{"castling against", "r3k2r/8/8/8/8/8/8/4K3 w kq - 0 1",
+		[]int{5, 130, 782, 22180}},
+	{"pawn covers f1", "4k3/8/8/8/8/8/4p3/4K2R w K - 0 1",
+		[]int{12}},
+	{"pawn covers g1", "4k3/8/8/8/8/8/7p/4K2R w K - 0 1",
+		[]int{8}},
+}
+
+// Positions built to catch a generator out on pins, checks, en passant and castling, with the node count
+// at one depth only (https://www.chessprogramming.org/Perft_Results and the suites that grew out of it).
+var PERFT_TRAPS = []struct{
+	name string
+	fen string
+	depth int
+	nodes int
+}{
+	{"illegal en passant, rook on the rank", "3k4/3p4/8/K1P4r/8/8/8/8 b - - 0 1", 6, 1134888},
+	{"en passant out of check", "8/8/4k3/8/2p5/8/B2P2K1/8 w - - 0 1", 6, 1015133},
+	{"en passant into check", "8/8/1k6/2b5/2pP4/8/5K2/8 b - d3 0 1", 6, 1440467},
+	{"short castling gives check", "5k2/8/8/8/8/8/8/4K2R w K - 0 1", 6, 661072},
+	{"long castling gives check", "3k4/8/8/8/8/8/8/R3K3 w Q - 0 1", 6, 803711},
+	{"castling and losing the right", "r3k2r/1b4bq/8/8/8/8/7B/R3K2R w KQkq - 0 1", 4, 1274206},
+	{"castling prevented", "r3k2r/8/3Q4/8/8/5q2/8/R3K2R b KQkq - 0 1", 4, 1720476},
+	{"promote out of check", "2K2r2/4P3/8/8/8/8/8/3k4 w - - 0 1", 6, 3821001},
+	{"discovered check", "8/8/1P2K3/8/2n5/1q6/8/5k2 b - - 0 1", 5, 1004658},
+	{"promote to give check", "4k3/1P6/8/8/8/8/K7/8 w - - 0 1", 6, 217342},
+	{"underpromote to check", "8/P1k5/K7/8/8/8/8/8 w - - 0 1", 6, 92683},
+	{"self stalemate", "K1k5/8/P7/8/8/8/8/8 w - - 0 1", 6, 2217},
+	{"stalemate and checkmate", "8/k1P5/8/1K6/8/8/8/8 w - - 0 1", 7, 567584},
+	{"double check", "8/8/2k5/5q2/5n2/8/5K2/8 b - - 0 1", 4, 23527},
+}
+
+func TestPerft(t *testing.T) {
+	for _, c := range PERFT_SUITE {
+		g := mustLoadFEN(t, c.fen)
+		for i, want := range c.nodes {
+			if testing.Short() && (want > 10000) {
+				break
+			}
+			if got := Perft(g, i + 1); got != want {
+				t.Errorf("%s: Perft(%d) = %d, want %d", c.name, i + 1, got, want)
+			}
+		}
+		if g.FEN() != c.fen {
+			t.Errorf("%s: perft left the position as %s", c.name, g.FEN())
+		}
+	}
+}
+
+func TestPerftTraps(t *testing.T) {
+	if testing.Short() {
+		t.Skip("deep perft")
+	}
+	for _, c := range PERFT_TRAPS {
+		g := mustLoadFEN(t, c.fen)
+		if got := Perft(g, c.depth); got != c.nodes {
+			t.Errorf("%s: Perft(%d) = %d, want %d", c.name, c.depth, got, c.nodes)
+		}
+	}
+}
+
+func TestDivide(t *testing.T) {
+	g := mustLoadFEN(t, "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1")
+	var out bytes.Buffer
+	if n := Divide(g, 2, &out); n != 264 {
+		t.Errorf("Divide(2) = %d, want 264", n)
+	}
+	for _, line := range []string{"b4c5: 42\n", "c4c5: 43\n", "g1h1: 46\n", "Moves: 6\n", "Nodes: 264\n"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("divide output missing %q:\n%s", line, out.String())
+		}
+	}
+
+	out.Reset()
+	if n := Divide(g, 0, &out); (n != 1) || !strings.Contains(out.String(), "Nodes: 1\n") {
+		t.Errorf("Divide(0) = %d:\n%s", n, out.String())
+	}
+}
+
+func BenchmarkPerft(b *testing.B) {
+	for _, c := range PERFT_SUITE[:2] {
+		g, _ := LoadFEN(c.fen)
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Perft(g, 3)
+			}
+		})
+	}
+}
@@ -0,0 +1,585 @@
+package board
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Portable Game Notation (http://www.saremba.de/chessgml/standards/pgn/pgn-complete.htm)
+
+// A PGNTag is one of a game's [Name "value"] pairs.
+type PGNTag struct{
+	N string    // tag name, e.g. White        ([N]ame)
+	V string    // tag value, unescaped        ([V]alue)
+}
+
+// A PGNMove is a move in a PGN game, with the annotations and variations that follow it.
+type PGNMove struct{
+	SAN string          // the move as it was written, move number and annotation glyphs removed
+	M Move              // the move itself, checked against the position it was played in ([M]ove)
+	C string            // comments following the move, joined by spaces                  ([C]omment)
+	N []int             // numeric annotation glyphs, $1 or ! for a good move and so on    ([N]AGs)
+	V [][]PGNMove       // variations: other moves that could have been played instead     ([V]ariations)
+}
+
+// A PGNGame is a game as ReadPGN reads it and WritePGN writes it.
+type PGNGame struct{
+	T []PGNTag          // tag pairs in the order they were written ([T]ags)
+	SC string           // comment before the first move            ([S]tarting [C]omment)
+	M []PGNMove         // the mainline                             ([M]oves)
+	R string            // 1-0, 0-1, 1/2-1/2 or *                   ([R]esult)
+	G *GameState        // the position after the mainline, with the mainline as its history ([G]ame)
+}
+
+// The Seven Tag Roster, which every PGN game should have, in the order they should be written.
+var SEVEN_TAG_ROSTER = [...]string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+var PGN_RESULTS = map[string]bool{"1-0": true, "0-1": true, "1/2-1/2": true, "*": true}
+
+// Move suffix annotations and the NAGs they stand for.
+var PGN_SUFFIXES = map[string]int{"!": 1, "?": 2, "!!": 3, "??": 4, "!?": 5, "?!": 6}
+
+// Tag is the value of the named tag, or "" if the game doesn't have it.
+func (game *PGNGame) Tag(name string) string {
+	for _, t := range game.T {
+		if t.N == name {
+			return t.V
+		}
+	}
+	return ""
+}
+
+// SetTag sets the named tag, adding it after the others if the game doesn't have it yet.
+func (game *PGNGame) SetTag(name string, value string) {
+	for i, t := range game.T {
+		if t.N == name {
+			game.T[i].V = value
+			return
+		}
+	}
+	game.T = append(game.T, PGNTag{N: name, V: value})
+}
+
+const (
+	pgnEOF = iota
+	pgnTag
+	pgnComment
+	pgnNAG
+	pgnOpen
+	pgnClose
+	pgnSymbol
+)
+
+type pgnToken struct{
+	kind int
+	text string   // for a tag, the name; for a NAG, the number; otherwise the token, without braces for a comment
+	value string  // for a tag, the value
+	line int
+}
+
+type pgnScanner struct{
+	r *bufio.Reader
+	line int          // line the next byte is on, from 1
+	column int        // column the next byte is in, from 0
+	lastColumn int    // column before the last read, for unread
+	peeked *pgnToken
+}
+
+func (s *pgnScanner) read() (byte, bool) {
+	c, err := s.r.ReadByte()
+	if err != nil {
+		return 0, false
+	}
+	s.lastColumn = s.column
+	s.column += 1
+	if c == '\n' {
+		s.line, s.column = s.line + 1, 0
+	}
+	return c, true
+}
+
+func (s *pgnScanner) unread(c byte) {
+	s.r.UnreadByte()
+	s.column = s.lastColumn
+	if c == '\n' {
+		s.line -= 1
+	}
+}
+
+func (s *pgnScanner) peek() (pgnToken, error) {
+	if s.peeked == nil {
+		t, err := s.scan()
+		if err != nil {
+			return t, err
+		}
+		s.peeked = &t
+	}
+	return *s.peeked, nil
+}
+
+func (s *pgnScanner) next() (pgnToken, error) {
+	t, err := s.peek()
+	s.peeked = nil
+	return t, err
+}
+
+func (s *pgnScanner) scan() (pgnToken, error) {
+	for {
+		atLineStart := s.column == 0
+		c, ok := s.read()
+		if !ok {
+			return pgnToken{kind: pgnEOF, line: s.line}, nil
+		}
+		line := s.line
+		switch {
+		case (c == '\n') || (c == ' ') || (c == '\t') || (c == '\r'):
+			continue
+		case (c == '%') && atLineStart:
+			// escape mechanism: the rest of the line is for other programs
+			s.skipLine()
+			continue
+		case c == ';':
+			text := s.skipLine()
+			return pgnToken{kind: pgnComment, text: strings.TrimSpace(text), line: line}, nil
+		case c == '{':
+			var sb strings.Builder
+			for {
+				c, ok := s.read()
+				if !ok {
+					return pgnToken{}, fmt.Errorf("Line %d: comment is never closed.", line)
+				}
+				if c == '}' {
+					break
+				}
+				sb.WriteByte(c)
+			}
+			return pgnToken{kind: pgnComment, text: strings.Join(strings.Fields(sb.String()), " "), line: line}, nil
+		case c == '[':
+			return s.scanTag(line)
+		case c == '(':
+			return pgnToken{kind: pgnOpen, text: "(", line: line}, nil
+		case c == ')':
+			return pgnToken{kind: pgnClose, text: ")", line: line}, nil
+		case c == '$':
+			digits := s.scanWhile(func(c byte) bool { return (c >= '0') && (c <= '9') })
+			if digits == "" {
+				return pgnToken{}, fmt.Errorf("Line %d: $ without an annotation glyph number.", line)
+			}
+			return pgnToken{kind: pgnNAG, text: digits, line: line}, nil
+		case isPGNSymbolChar(c) || (c == '!') || (c == '?'):
+			s.unread(c)
+			text := s.scanWhile(func(c byte) bool { return isPGNSymbolChar(c) || (c == '!') || (c == '?') || (c == '.') })
+			return pgnToken{kind: pgnSymbol, text: text, line: line}, nil
+		case c == '.':
+			// move number indications are only for readers
+			continue
+		default:
+			return pgnToken{}, fmt.Errorf("Line %d: unexpected character %q.", line, c)
+		}
+	}
+}
+
+func isPGNSymbolChar(c byte) bool {
+	return ((c >= 'a') && (c <= 'z')) || ((c >= 'A') && (c <= 'Z')) || ((c >= '0') && (c <= '9')) ||
+		strings.IndexByte("_+#=:-/*", c) >= 0
+}
+
+func (s *pgnScanner) scanWhile(f func(byte) bool) string {
+	var sb strings.Builder
+	for {
+		c, ok := s.read()
+		if !ok {
+			break
+		}
+		if !f(c) {
+			s.unread(c)
+			break
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+func (s *pgnScanner) skipLine() string {
+	return s.scanWhile(func(c byte) bool { return c != '\n' })
+}
+
+func (s *pgnScanner) scanTag(line int) (pgnToken, error) {
+	// [Name "value"], with \" and \\ escaped in the value
+	s.scanWhile(func(c byte) bool { return (c == ' ') || (c == '\t') })
+	name := s.scanWhile(func(c byte) bool { return isPGNSymbolChar(c) })
+	s.scanWhile(func(c byte) bool { return (c == ' ') || (c == '\t') })
+	if c, ok := s.read(); (name == "") || !ok || (c != '"') {
+		return pgnToken{}, fmt.Errorf("Line %d: malformed tag pair.", line)
+	}
+	var sb strings.Builder
+	for {
+		c, ok := s.read()
+		if !ok || (c == '\n') {
+			return pgnToken{}, fmt.Errorf("Line %d: tag %s has an unterminated value.", line, name)
+		}
+		if c == '"' {
+			break
+		}
+		if c == '\\' {
+			if c, ok = s.read(); !ok {
+				return pgnToken{}, fmt.Errorf("Line %d: tag %s has an unterminated value.", line, name)
+			}
+		}
+		sb.WriteByte(c)
+	}
+	s.scanWhile(func(c byte) bool { return (c == ' ') || (c == '\t') })
+	if c, ok := s.read(); !ok || (c != ']') {
+		return pgnToken{}, fmt.Errorf("Line %d: tag %s is not closed with ].", line, name)
+	}
+	return pgnToken{kind: pgnTag, text: name, value: sb.String(), line: line}, nil
+}
+
+// ReadPGN reads every game in r, checking each move by replaying it from the position it was played in.
+// It returns the games read before any error.
+func ReadPGN(r io.Reader) ([]*PGNGame, error) {
+	s := &pgnScanner{r: bufio.NewReader(r), line: 1}
+	games := make([]*PGNGame, 0)
+	for {
+		t, err := s.peek()
+		if err != nil {
+			return games, err
+		}
+		if t.kind == pgnEOF {
+			return games, nil
+		}
+		game, err := readPGNGame(s)
+		if err != nil {
+			return games, fmt.Errorf("Game %d: %v", len(games) + 1, err)
+		}
+		games = append(games, game)
+	}
+}
+
+func readPGNGame(s *pgnScanner) (*PGNGame, error) {
+	game := &PGNGame{R: "*"}
+	for {
+		t, err := s.peek()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind != pgnTag {
+			break
+		}
+		s.next()
+		game.SetTag(t.text, t.value)
+	}
+
+	// A game that doesn't start from the usual position says where it does start with SetUp and FEN tags.
+	fen := START_FEN
+	if game.Tag("FEN") != "" {
+		fen = game.Tag("FEN")
+	}
+	g, err := LoadFEN(fen)
+	if err != nil {
+		return nil, err
+	}
+
+	game.M, game.SC, err = readPGNLine(s, g, 0)
+	if err != nil {
+		return nil, err
+	}
+	game.G = g
+
+	t, err := s.peek()
+	if err != nil {
+		return nil, err
+	}
+	if (t.kind == pgnSymbol) && PGN_RESULTS[t.text] {
+		s.next()
+		game.R = t.text
+	} else if PGN_RESULTS[game.Tag("Result")] {
+		// a game without a result token after its moves (a truncated file, say) can still have the tag
+		game.R = game.Tag("Result")
+	}
+	return game, nil
+}
+
+func readPGNLine(s *pgnScanner, g *GameState, depth int) ([]PGNMove, string, error) {
+	// Moves from g until the end of the game or, inside a variation (depth > 0), the closing parenthesis,
+	// played onto g as they are read. Also returns any comment before the first move.
+	moves := make([]PGNMove, 0)
+	before := ""
+	for {
+		t, err := s.peek()
+		if err != nil {
+			return nil, "", err
+		}
+		switch t.kind {
+		case pgnEOF, pgnTag:
+			if depth > 0 {
+				return nil, "", fmt.Errorf("Line %d: variation is never closed.", t.line)
+			}
+			return moves, before, nil
+		case pgnClose:
+			if depth == 0 {
+				return nil, "", fmt.Errorf("Line %d: unexpected ).", t.line)
+			}
+			return moves, before, nil
+		case pgnComment:
+			s.next()
+			if len(moves) == 0 {
+				before = joinComments(before, t.text)
+			} else {
+				moves[len(moves) - 1].C = joinComments(moves[len(moves) - 1].C, t.text)
+			}
+		case pgnNAG:
+			s.next()
+			if len(moves) == 0 {
+				return nil, "", fmt.Errorf("Line %d: annotation glyph before any move.", t.line)
+			}
+			n, _ := strconv.Atoi(t.text)
+			moves[len(moves) - 1].N = append(moves[len(moves) - 1].N, n)
+		case pgnOpen:
+			s.next()
+			if len(moves) == 0 {
+				return nil, "", fmt.Errorf("Line %d: variation before any move.", t.line)
+			}
+			// a variation replaces the move before it, so it starts from the position that move was played in
+			tempG := g.Copy()
+			UndoMove(tempG)
+			variation, comment, err := readPGNLine(s, tempG, depth + 1)
+			if err != nil {
+				return nil, "", err
+			}
+			if comment != "" && len(variation) > 0 {
+				// keep a comment that opens a variation with its first move rather than lose it
+				variation[0].C = joinComments(comment, variation[0].C)
+			}
+			s.next()
+			last := &moves[len(moves) - 1]
+			last.V = append(last.V, variation)
+		case pgnSymbol:
+			if PGN_RESULTS[t.text] {
+				if depth > 0 {
+					// some programs end variations with a result too
+					s.next()
+					continue
+				}
+				return moves, before, nil
+			}
+			s.next()
+			if isMoveNumber(t.text) {
+				continue
+			}
+			if n, ok := PGN_SUFFIXES[t.text]; ok && (len(moves) > 0) {
+				// an annotation written apart from its move: e4 !?
+				moves[len(moves) - 1].N = append(moves[len(moves) - 1].N, n)
+				continue
+			}
+			move, err := readPGNMove(g, t)
+			if err != nil {
+				return nil, "", err
+			}
+			moves = append(moves, move)
+		}
+	}
+}
+
+func readPGNMove(g *GameState, t pgnToken) (PGNMove, error) {
+	// the move in t, which may still have its move number in front (12.Nf3) and annotation glyphs behind it
+	text := t.text
+	if i := strings.LastIndexByte(text, '.'); i >= 0 {
+		text = text[i + 1:]
+	}
+	move := PGNMove{}
+	if suffix := strings.TrimLeft(text, "abcdefghKQRBNOP0123456789x=-+#:"); suffix != "" {
+		n, ok := PGN_SUFFIXES[suffix]
+		if !ok {
+			return move, fmt.Errorf("Line %d: unknown annotation %q.", t.line, suffix)
+		}
+		move.N = append(move.N, n)
+		text = strings.TrimSuffix(text, suffix)
+	}
+	m, err := ParseMove(g, text)
+	if err != nil {
+		return move, fmt.Errorf("Line %d, move %d: %v", t.line, g.FN, err)
+	}
+	move.SAN, move.M = text, m
+	MakeMove(g, m)
+	return move, nil
+}
+
+func isMoveNumber(s string) bool {
+	// 12 or 12. or 12...
+	digits := strings.TrimRight(s, ".")
+	if digits == "" {
+		return false
+	}
+	_, err := strconv.Atoi(digits)
+	return err == nil
+}
+
+func joinComments(a string, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + " " + b
+}
+
+// NewPGNGame makes a PGN record of the game played in g, for WritePGN.
+func NewPGNGame(g *GameState, result string, white string, black string, date time.Time) *PGNGame {
+	game := &PGNGame{R: result, G: g}
+	for _, name := range SEVEN_TAG_ROSTER {
+		game.SetTag(name, "?")
+	}
+	game.SetTag("Date", date.Format("2006.01.02"))
+	game.SetTag("White", white)
+	game.SetTag("Black", black)
+	game.SetTag("Result", result)
+	if g.SP != START_FEN {
+		game.SetTag("SetUp", "1")
+		game.SetTag("FEN", g.SP)
+	}
+	for i, san := range g.PlayedSAN() {
+		game.M = append(game.M, PGNMove{SAN: san, M: g.H[i].M})
+	}
+	return game
+}
+
+// PGNResult is the PGN result token for a game in g that ended with result; a game still going is "*".
+func PGNResult(g *GameState, result GameResult) string {
+	switch {
+	case result == IN_PROGRESS:
+		return "*"
+	case result.IsDraw():
+		return "1/2-1/2"
+	case g.PL == 1:
+		return "1-0"
+	}
+	return "0-1"
+}
+
+// WritePGN writes game in PGN export format: the Seven Tag Roster first and in order, then any other tags,
+// then the moves with their comments, annotation glyphs and variations, in lines of at most 79 characters.
+func WritePGN(w io.Writer, game *PGNGame) error {
+	var sb strings.Builder
+	for _, name := range SEVEN_TAG_ROSTER {
+		value := game.Tag(name)
+		if value == "" {
+			value = "?"
+		}
+		if name == "Result" {
+			value = game.R
+		}
+		writePGNTag(&sb, name, value)
+	}
+	for _, t := range game.T {
+		if !isSevenTagRoster(t.N) {
+			writePGNTag(&sb, t.N, t.V)
+		}
+	}
+	sb.WriteString("\n")
+
+	fen := START_FEN
+	if game.Tag("FEN") != "" {
+		fen = game.Tag("FEN")
+	}
+	g, err := LoadFEN(fen)
+	if err != nil {
+		return err
+	}
+	tokens := make([]string, 0)
+	if game.SC != "" {
+		tokens = append(tokens, "{" + game.SC + "}")
+	}
+	tokens = appendPGNLine(tokens, g, game.M)
+	tokens = append(tokens, game.R)
+	sb.WriteString(wrapPGN(tokens, 79))
+	sb.WriteString("\n\n")
+
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+func writePGNTag(sb *strings.Builder, name string, value string) {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	fmt.Fprintf(sb, "[%s \"%s\"]\n", name, value)
+}
+
+func isSevenTagRoster(name string) bool {
+	for _, n := range SEVEN_TAG_ROSTER {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func appendPGNLine(tokens []string, g *GameState, moves []PGNMove) []string {
+	// moves, played from g, as movetext tokens. Black's moves get their own number (12...) wherever the
+	// flow of moves is broken by a comment or variation, and at the start.
+	tempG := g.Copy()
+	numbered := false
+	for _, move := range moves {
+		// a move number stays on the same line as its move
+		if tempG.PL == 0 {
+			tokens = append(tokens, fmt.Sprintf("%d. %s", tempG.FN, tempG.SAN(move.M)))
+		} else if !numbered {
+			tokens = append(tokens, fmt.Sprintf("%d... %s", tempG.FN, tempG.SAN(move.M)))
+		} else {
+			tokens = append(tokens, tempG.SAN(move.M))
+		}
+		numbered = true
+		for _, n := range move.N {
+			tokens = append(tokens, fmt.Sprintf("$%d", n))
+		}
+		if move.C != "" {
+			tokens = append(tokens, strings.Fields("{" + move.C + "}")...)
+			numbered = false
+		}
+		for _, variation := range move.V {
+			inner := appendPGNLine(nil, tempG, variation)
+			if len(inner) > 0 {
+				inner[0] = "(" + inner[0]
+				inner[len(inner) - 1] += ")"
+			}
+			tokens = append(tokens, inner...)
+			numbered = false
+		}
+		MakeMove(tempG, move.M)
+	}
+	return tokens
+}
+
+func wrapPGN(tokens []string, width int) string {
+	var sb strings.Builder
+	column := 0
+	for _, t := range tokens {
+		if (column > 0) && (column + 1 + len(t) > width) {
+			sb.WriteString("\n")
+			column = 0
+		}
+		if column > 0 {
+			sb.WriteString(" ")
+			column += 1
+		}
+		sb.WriteString(t)
+		column += len(t)
+	}
+	return sb.String()
+}
+
+// SavePGN writes game to the file at path, replacing anything already there.
+func SavePGN(path string, game *PGNGame) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WritePGN(f, game); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
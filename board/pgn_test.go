@@ -0,0 +1,186 @@
+package board
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const PGN_SAMPLE = `[Event "F/S Return Match"]
+[Site "Belgrade, Serbia JUG"]
+[Date "1992.11.04"]
+[Round "29"]
+[White "Fischer, Robert J."]
+[Black "Spassky, Boris V."]
+[Result "1/2-1/2"]
+[Annotator "Someone \"quoted\""]
+
+{A comment before the moves.}
+1. e4 e5 2. Nf3 Nc6 3. Bb5 {This opening is called the Ruy Lopez.} 3... a6
+4. Ba4 Nf6 5. O-O Be7 6. Re1 b5 7. Bb3 d6 8. c3 O-O 9. h3 Nb8 10. d4 Nbd7
+11. c4 c6 12. cxb5 axb5 13. Nc3 Bb7 14. Bg5 b4 15. Nb1 h6 16. Bh4 c5 17. dxe5
+Nxe4 18. Bxe7 Qxe7 19. exd6 Qf6 20. Nbd2 Nxd6 21. Nc4 Nxc4 22. Bxc4 Nb6
+23. Ne5 Rae8 24. Bxf7+ Rxf7 25. Nxf7 Rxe1+ 26. Qxe1 Kxf7 27. Qe3 Qg5 28. Qxg5
+hxg5 29. b3 Ke6 30. a3 Kd6 31. axb4 cxb4 32. Ra5 Nd5 33. f3 Bc8 34. Kf2 Bf5
+35. Ra7 g6 36. Ra6+ Kc5 37. Ke1 Nf4 38. g3 Nxh3 39. Kd2 Kb5 40. Rd6 Kc5 41. Ra6
+Nf2 42. g4 Bd3 43. Re6 1/2-1/2
+
+[Event "Annotated"]
+[Site "?"]
+[Date "????.??.??"]
+[Round "?"]
+[White "?"]
+[Black "?"]
+[Result "1-0"]
+
+% a line for some other program
+1. e4! e5 $1 2. Qh5 ; an early queen
+Nc6?! (2... g6 3. Qf3 (3. Qxe5+ Qe7) 3... Nf6) (2... Qe7) 3. Bc4 Nf6?? 4. Qxf7# 1-0
+
+[Event "Set up"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1"]
+
+1. e4 Kd7 *
+`
+
+func TestReadPGN(t *testing.T) {
+	games, err := ReadPGN(strings.NewReader(PGN_SAMPLE))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 3 {
+		t.Fatalf("read %d games, want 3", len(games))
+	}
+
+	fischer := games[0]
+	if (fischer.Tag("White") != "Fischer, Robert J.") || (fischer.Tag("Annotator") != `Someone "quoted"`) {
+		t.Errorf("tags = %v", fischer.T)
+	}
+	if (len(fischer.M) != 85) || (fischer.R != "1/2-1/2") {
+		t.Errorf("%d moves, result %s; want 85, 1/2-1/2", len(fischer.M), fischer.R)
+	}
+	if fischer.SC != "A comment before the moves." {
+		t.Errorf("starting comment = %q", fischer.SC)
+	}
+	if fischer.M[4].C != "This opening is called the Ruy Lopez." {
+		t.Errorf("comment after 3. Bb5 = %q", fischer.M[4].C)
+	}
+	if fen := fischer.G.FEN(); fen != "8/8/4R1p1/2k3p1/1p4P1/1P1b1P2/3K1n2/8 b - - 2 43" {
+		t.Errorf("final position = %s", fen)
+	}
+
+	annotated := games[1]
+	if (len(annotated.M) != 7) || (annotated.R != "1-0") || (Result(annotated.G) != CHECKMATE) {
+		t.Errorf("%d moves, result %s, %v; want 7, 1-0, checkmate", len(annotated.M), annotated.R, Result(annotated.G))
+	}
+	if (len(annotated.M[0].N) != 1) || (annotated.M[0].N[0] != 1) || (annotated.M[1].N[0] != 1) ||
+		(annotated.M[3].N[0] != 6) || (annotated.M[5].N[0] != 4) {
+		t.Errorf("annotation glyphs not read")
+	}
+	if annotated.M[2].C != "an early queen" {
+		t.Errorf("comment after 2. Qh5 = %q", annotated.M[2].C)
+	}
+	if len(annotated.M[3].V) != 2 {
+		t.Fatalf("2... Nc6 has %d variations, want 2", len(annotated.M[3].V))
+	}
+	variation := annotated.M[3].V[0]
+	if (len(variation) != 3) || (variation[0].SAN != "g6") || (len(variation[1].V) != 1) ||
+		(variation[1].V[0][0].SAN != "Qxe5+") {
+		t.Errorf("variation = %+v", variation)
+	}
+
+	setUp := games[2]
+	if (len(setUp.M) != 2) || (setUp.R != "*") || (setUp.G.FEN() != "8/3k4/8/8/4P3/8/8/4K3 w - - 1 2") {
+		t.Errorf("game from a set up position ends at %s after %d moves", setUp.G.FEN(), len(setUp.M))
+	}
+}
+
+func TestReadPGNErrors(t *testing.T) {
+	cases := []struct{
+		pgn string
+		err string
+	}{
+		{"1. e4 e5 2. Ke3 *", `Game 1: Line 1, move 2: Illegal move "Ke3".`},
+		{"1. e4 (1. d4 d5 *", "Game 1: Line 1: variation is never closed."},
+		{"1. e4 {unfinished", "Game 1: Line 1: comment is never closed."},
+		{"[White \"x\"]\n1. e4 *\n\n[Black x]\n1. d4 *", "Line 4: malformed tag pair."},
+		{"1. e4 e5 ) *", "Game 1: Line 1: unexpected )."},
+	}
+	for _, c := range cases {
+		_, err := ReadPGN(strings.NewReader(c.pgn))
+		if (err == nil) || (err.Error() != c.err) {
+			t.Errorf("ReadPGN(%q) error = %v, want %s", c.pgn, err, c.err)
+		}
+	}
+}
+
+func TestWritePGN(t *testing.T) {
+	games, err := ReadPGN(strings.NewReader(PGN_SAMPLE))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	for _, game := range games {
+		if err := WritePGN(&out, game); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, line := range strings.Split(out.String(), "\n") {
+		if len(line) > 79 {
+			t.Errorf("line longer than 79 characters: %q", line)
+		}
+	}
+	for _, want := range []string{
+		"[Event \"F/S Return Match\"]\n[Site \"Belgrade, Serbia JUG\"]\n[Date \"1992.11.04\"]\n",
+		"[Annotator \"Someone \\\"quoted\\\"\"]\n\n{A comment before the moves.} 1. e4 e5 2. Nf3 Nc6 3. Bb5 {This opening is\n",
+		"called the Ruy Lopez.} 3... a6 4. Ba4",
+		"1. e4 $1 e5 $1 2. Qh5 {an early queen} 2... Nc6 $6 (2... g6 3. Qf3 (3. Qxe5+\nQe7) 3... Nf6) (2... Qe7) 3. Bc4 Nf6 $4",
+		"4. Qxf7# 1-0\n",
+		"[Result \"*\"]\n[SetUp \"1\"]\n[FEN \"4k3/8/8/8/8/8/4P3/4K3 w - - 0 1\"]\n\n1. e4 Kd7 *\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	// and reading it back gives the same games
+	again, err := ReadPGN(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, game := range games {
+		if (again[i].G.FEN() != game.G.FEN()) || (again[i].R != game.R) {
+			t.Errorf("game %d changed after writing and reading back", i + 1)
+		}
+		for _, tag := range game.T {
+			if again[i].Tag(tag.N) != tag.V {
+				t.Errorf("game %d: tag %s changed from %q to %q", i + 1, tag.N, tag.V, again[i].Tag(tag.N))
+			}
+		}
+	}
+}
+
+func TestNewPGNGame(t *testing.T) {
+	g := mustLoadFEN(t, START_FEN)
+	for _, m := range SCHOLAR_MATE {
+		MakeMove(g, m)
+	}
+	game := NewPGNGame(g, PGNResult(g, Result(g)), "Alice", "Bob", time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC))
+	var out strings.Builder
+	WritePGN(&out, game)
+	want := `[Event "?"]
+[Site "?"]
+[Date "2024.03.09"]
+[Round "?"]
+[White "Alice"]
+[Black "Bob"]
+[Result "1-0"]
+
+1. e4 f6 2. d4 g5 3. Qh5# 1-0
+
+`
+	if out.String() != want {
+		t.Errorf("writePGN =\n%s\nwant\n%s", out.String(), want)
+	}
+}
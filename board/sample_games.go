@@ -1,4 +1,4 @@
-package main
+package board
 
 var SCHOLAR_MATE MoveSequence = MoveSequence{Move{PL: 0, SF: 'E', SR: 2, DF: 'E', DR: 4, P: WHITE_PAWN}, 
 											 Move{PL: 1, SF: 'F', SR: 7, DF: 'F', DR: 6, P: BLACK_PAWN}, 
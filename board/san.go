@@ -0,0 +1,172 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SAN writes m, a legal move in g, in Standard Algebraic Notation
+// (https://en.wikipedia.org/wiki/Algebraic_notation_(chess)): Nf3, exd5, Nbd2, R1e2, O-O-O, e8=Q+, Qh5#
+func (g *GameState) SAN(m Move) string {
+	var sb strings.Builder
+	moved := g.B.At(m.SF, m.SR)
+	capture := (g.B.At(m.DF, m.DR) != EMPTY_SQUARE) || IsEnPassant(g, m)
+
+	switch {
+	case IsCastling(moved, m) && (m.DF == 'G'):
+		sb.WriteString("O-O")
+	case IsCastling(moved, m):
+		sb.WriteString("O-O-O")
+	case moved & TYPE_MASK == PAWN:
+		if capture {
+			sb.WriteString(strings.ToLower(string(rune(m.SF))) + "x")
+		}
+		sb.WriteString(SquareName(m.DF, m.DR))
+		if m.P != moved {
+			sb.WriteString("=" + PIECE_NAMES[m.P])
+		}
+	default:
+		sb.WriteString(PIECE_NAMES[moved])
+		sb.WriteString(disambiguation(g, m, moved))
+		if capture {
+			sb.WriteString("x")
+		}
+		sb.WriteString(SquareName(m.DF, m.DR))
+	}
+
+	e := PlayMove(g, m)
+	if CheckForCheck(g, g.PL) {
+		if CheckNoLegalMoves(g) {
+			sb.WriteString("#")
+		} else {
+			sb.WriteString("+")
+		}
+	}
+	TakeBack(g, e)
+	return sb.String()
+}
+
+func disambiguation(g *GameState, m Move, moved Piece) string {
+	// What SAN adds after the piece letter when another piece of the same kind could also move to m's
+	// destination: the file if that tells them apart, otherwise the rank, otherwise both.
+	sameFile, sameRank, others := false, false, false
+	for _, other := range GenerateMoves(g, ALL_MOVES) {
+		if (other.DF != m.DF) || (other.DR != m.DR) || ((other.SF == m.SF) && (other.SR == m.SR)) ||
+			(g.B.At(other.SF, other.SR) != moved) {
+			continue
+		}
+		others = true
+		sameFile = sameFile || (other.SF == m.SF)
+		sameRank = sameRank || (other.SR == m.SR)
+	}
+	name := SquareName(m.SF, m.SR)
+	switch {
+	case !others:
+		return ""
+	case !sameFile:
+		return name[:1]
+	case !sameRank:
+		return name[1:]
+	}
+	return name
+}
+
+// PlayedSAN is the moves played since the starting position, in SAN.
+func (g *GameState) PlayedSAN() []string {
+	tempG, err := LoadFEN(g.SP)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, len(g.H))
+	for i, e := range g.H {
+		names[i] = tempG.SAN(e.M)
+		MakeMove(tempG, e.M)
+	}
+	return names
+}
+
+var SAN_PIECES = map[byte]Piece{'N': KNIGHT, 'B': BISHOP, 'R': ROOK, 'Q': QUEEN, 'K': KING}
+
+// ParseMove returns the legal move s names, written in SAN (Nf3, exd6, O-O, e8=N+), long algebraic
+// (Ng1-f3, e7xd8=Q) or UCI coordinates (e2e4, e7e8q), or an error saying why s is malformed, illegal
+// or ambiguous.
+func ParseMove(g *GameState, s string) (Move, error) {
+	text := strings.TrimRight(strings.TrimSpace(s), "+#!?")
+	if text == "" {
+		return Move{}, fmt.Errorf("Invalid move %q.", s)
+	}
+	if (text == "O-O") || (text == "0-0") || (text == "O-O-O") || (text == "0-0-0") {
+		return parseCastling(g, s, len(text) == 5)
+	}
+
+	// [PIECE] [FROM FILE] [FROM RANK] [-|x] [TO SQUARE] [[=]PROMOTION], with only the destination required
+	kind, named := PAWN, false
+	if p, ok := SAN_PIECES[text[0]]; ok {
+		kind, named, text = p, true, text[1:]
+	}
+	// a square always ends in a digit, so a letter at the end is a promotion (upper case in SAN, lower in UCI)
+	promotion := EMPTY_SQUARE
+	if n := len(text); n > 0 {
+		if p, ok := SAN_PIECES[strings.ToUpper(text[n - 1:])[0]]; ok {
+			promotion, text = p, strings.TrimSuffix(text[:n - 1], "=")
+		}
+	}
+	if (len(text) < 2) || ((promotion != EMPTY_SQUARE) && ((kind != PAWN) || (promotion == KING))) {
+		return Move{}, fmt.Errorf("Invalid move %q.", s)
+	}
+	df, dr, err := ParseSquare(text[len(text) - 2:])
+	if err != nil {
+		return Move{}, fmt.Errorf("Invalid move %q: bad destination square.", s)
+	}
+	from := strings.TrimRight(text[:len(text) - 2], "-x")
+	sf, sr := 0, 0
+	for _, c := range []byte(from) {
+		switch {
+		case (c >= 'a') && (c <= 'h') && (sf == 0) && (sr == 0):
+			sf = int(c - 'a') + 'A'
+		case (c >= '1') && (c <= '8') && (sr == 0):
+			sr = int(c - '0')
+		default:
+			return Move{}, fmt.Errorf("Invalid move %q.", s)
+		}
+	}
+
+	matches := MoveSequence{}
+	for _, m := range LegalMoves(g) {
+		moved := g.B.At(m.SF, m.SR)
+		if (m.DF != df) || (m.DR != dr) || ((sf != 0) && (m.SF != sf)) || ((sr != 0) && (m.SR != sr)) {
+			continue
+		}
+		// coordinates alone (g1f3) say where a piece starts but not what it is
+		if (moved & TYPE_MASK != kind) && (named || (sf == 0) || (sr == 0)) {
+			continue
+		}
+		if (promotion != EMPTY_SQUARE) && (m.P & TYPE_MASK != promotion) {
+			continue
+		}
+		if (promotion == EMPTY_SQUARE) && (m.P != moved) {
+			return Move{}, fmt.Errorf("Move %q must say which piece the pawn promotes to.", s)
+		}
+		matches = append(matches, m)
+	}
+	switch len(matches) {
+	case 0:
+		return Move{}, fmt.Errorf("Illegal move %q.", s)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = g.SAN(m)
+	}
+	return Move{}, fmt.Errorf("Ambiguous move %q: could be %s.", s, strings.Join(names, " or "))
+}
+
+func parseCastling(g *GameState, s string, queenside bool) (Move, error) {
+	for _, m := range LegalMoves(g) {
+		if IsCastling(g.B.At(m.SF, m.SR), m) && ((m.DF == 'C') == queenside) {
+			return m, nil
+		}
+	}
+	return Move{}, fmt.Errorf("Illegal move %q: castling is not possible.", s)
+}
@@ -0,0 +1,165 @@
+package board
+
+import (
+	"testing"
+)
+
+func TestSAN(t *testing.T) {
+	cases := []struct{
+		fen string
+		sf, sr, df, dr int
+		promotion Piece
+		want string
+	}{
+		{START_FEN, 'G', 1, 'F', 3, 0, "Nf3"},
+		{START_FEN, 'E', 2, 'E', 4, 0, "e4"},
+		{"rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 0 2", 'E', 4, 'D', 5, 0, "exd5"},
+		{"rnbqkbnr/pppppppp/8/8/8/5N2/PPPPPPPP/RNBQKB1R w KQkq - 0 1", 'B', 1, 'D', 2, 0, ""},
+		{"rnbqkbnr/ppp1pppp/8/8/8/5N2/PPP1PPPP/RNBQKB1R w KQkq - 0 1", 'B', 1, 'D', 2, 0, "Nbd2"},
+		{"4k3/8/8/8/8/8/4K3/R6R w - - 0 1", 'A', 1, 'D', 1, 0, "Rad1"},
+		{"4k3/R7/8/8/8/8/4K3/R7 w - - 0 1", 'A', 1, 'A', 4, 0, "R1a4"},
+		{"2k5/8/8/8/4Q2Q/8/K7/7Q w - - 0 1", 'H', 4, 'E', 1, 0, "Qh4e1"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", 'E', 1, 'G', 1, 0, "O-O"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", 'E', 8, 'C', 8, 0, "O-O-O"},
+		{"2k5/4P3/8/8/8/8/8/4K3 w - - 0 1", 'E', 7, 'E', 8, WHITE_QUEEN, "e8=Q+"},
+		{"2k2r2/4P3/8/8/8/8/8/4K3 w - - 0 1", 'E', 7, 'F', 8, WHITE_KNIGHT, "exf8=N"},
+		{"rnbqkbnr/ppppp2p/5p2/6p1/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 0 3", 'D', 1, 'H', 5, 0, "Qh5#"},
+		{"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3", 'E', 5, 'F', 6, 0, "exf6"},
+	}
+	for _, c := range cases {
+		g := mustLoadFEN(t, c.fen)
+		m, ok := Move{}, false
+		for _, candidate := range LegalMoves(g) {
+			if (candidate.SF == c.sf) && (candidate.SR == c.sr) && (candidate.DF == c.df) && (candidate.DR == c.dr) &&
+				((c.promotion == 0) || (candidate.P == c.promotion)) {
+				m, ok = candidate, true
+			}
+		}
+		if c.want == "" {
+			if ok {
+				t.Errorf("%s: %v should be illegal", c.fen, m)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s: no legal move %c%d%c%d", c.fen, c.sf, c.sr, c.df, c.dr)
+			continue
+		}
+		if got := g.SAN(m); got != c.want {
+			t.Errorf("%s: SAN = %q, want %q", c.fen, got, c.want)
+		}
+		if g.FEN() != c.fen {
+			t.Errorf("%s: SAN left the position as %s", c.fen, g.FEN())
+		}
+	}
+}
+
+func TestPlayedSAN(t *testing.T) {
+	g := mustLoadFEN(t, START_FEN)
+	for _, s := range [][4]int{{'E', 2, 'E', 4}, {'E', 7, 'E', 5}, {'D', 1, 'H', 5}, {'B', 8, 'C', 6},
+		{'F', 1, 'C', 4}, {'G', 8, 'F', 6}, {'H', 5, 'F', 7}} {
+		m, _ := findMove(g, s[0], s[1], s[2], s[3])
+		MakeMove(g, m)
+	}
+	want := []string{"e4", "e5", "Qh5", "Nc6", "Bc4", "Nf6", "Qxf7#"}
+	got := g.PlayedSAN()
+	for i := range want {
+		if (i >= len(got)) || (got[i] != want[i]) {
+			t.Fatalf("playedSAN = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestParseMove(t *testing.T) {
+	cases := []struct{
+		fen string
+		input string
+		want string   // the move in SAN, or "" if input should be rejected
+	}{
+		{START_FEN, "Nf3", "Nf3"},
+		{START_FEN, "e4", "e4"},
+		{START_FEN, "e2e4", "e4"},
+		{START_FEN, "g1f3", "Nf3"},
+		{START_FEN, "Ng1-f3", "Nf3"},
+		{START_FEN, "e2-e4", "e4"},
+		{START_FEN, "e5", ""},
+		{START_FEN, "Nf4", ""},
+		{START_FEN, "Zf3", ""},
+		{START_FEN, "e9", ""},
+		{START_FEN, "", ""},
+		{START_FEN, "O-O", ""},
+		{"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3", "exf6", "exf6"},
+		{"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3", "exd6", ""},
+		{"rnbqkbnr/ppp1pppp/8/8/8/5N2/PPP1PPPP/RNBQKB1R w KQkq - 0 1", "Nd2", ""},
+		{"rnbqkbnr/ppp1pppp/8/8/8/5N2/PPP1PPPP/RNBQKB1R w KQkq - 0 1", "Nbd2", "Nbd2"},
+		{"rnbqkbnr/ppp1pppp/8/8/8/5N2/PPP1PPPP/RNBQKB1R w KQkq - 0 1", "Nfd2", "Nfd2"},
+		{"2k5/8/8/8/4Q2Q/8/K7/7Q w - - 0 1", "Qe1", ""},
+		{"2k5/8/8/8/4Q2Q/8/K7/7Q w - - 0 1", "Q4e1", ""},
+		{"2k5/8/8/8/4Q2Q/8/K7/7Q w - - 0 1", "Qh4e1", "Qh4e1"},
+		{"2k5/8/8/8/4Q2Q/8/K7/7Q w - - 0 1", "Qhxe1", ""},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "O-O", "O-O"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "0-0-0", "O-O-O"},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1g1", "O-O"},
+		{"2k5/4P3/8/8/8/8/8/4K3 w - - 0 1", "e8=N", "e8=N"},
+		{"2k5/4P3/8/8/8/8/8/4K3 w - - 0 1", "e8Q+", "e8=Q+"},
+		{"2k5/4P3/8/8/8/8/8/4K3 w - - 0 1", "e7e8q", "e8=Q+"},
+		{"2k5/4P3/8/8/8/8/8/4K3 w - - 0 1", "e8", ""},
+		{"2k5/4P3/8/8/8/8/8/4K3 w - - 0 1", "e8=K", ""},
+		{"rnbqkbnr/ppppp2p/5p2/6p1/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 0 3", "Qh5#", "Qh5#"},
+		{"rnbqkbnr/ppppp2p/5p2/6p1/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 0 3", "Qh5!?", "Qh5#"},
+	}
+	for _, c := range cases {
+		g := mustLoadFEN(t, c.fen)
+		m, err := ParseMove(g, c.input)
+		if c.want == "" {
+			if err == nil {
+				t.Errorf("%s: ParseMove(%q) = %v, want an error", c.fen, c.input, g.SAN(m))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: ParseMove(%q): %v", c.fen, c.input, err)
+		} else if got := g.SAN(m); got != c.want {
+			t.Errorf("%s: ParseMove(%q) = %s, want %s", c.fen, c.input, got, c.want)
+		}
+	}
+}
+
+func TestParseMoveErrors(t *testing.T) {
+	g := mustLoadFEN(t, "rnbqkbnr/ppp1pppp/8/8/8/5N2/PPP1PPPP/RNBQKB1R w KQkq - 0 1")
+	_, err := ParseMove(g, "Nd2")
+	if (err == nil) || (err.Error() != `Ambiguous move "Nd2": could be Nbd2 or Nfd2.`) {
+		t.Errorf("ambiguous move error = %v", err)
+	}
+}
+
+func TestSANRoundTrip(t *testing.T) {
+	// every legal move's SAN and UCI coordinates parse back to the same move
+	for _, c := range PERFT_SUITE {
+		g := mustLoadFEN(t, c.fen)
+		for _, m := range LegalMoves(g) {
+			for _, s := range []string{g.SAN(m), CoordinateNotation(g, m)} {
+				if back, err := ParseMove(g, s); (err != nil) || (back != m) {
+					t.Errorf("%s: %q parsed as %v, %v", c.name, s, back, err)
+				}
+			}
+		}
+	}
+}
+
+func TestParseSampleGame(t *testing.T) {
+	g := mustLoadFEN(t, START_FEN)
+	for i, s := range []string{"e4", "f6", "d4", "g5", "Qh5#"} {
+		m, err := ParseMove(g, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m != SCHOLAR_MATE[i] {
+			t.Errorf("%s parsed as %+v, want %+v", s, m, SCHOLAR_MATE[i])
+		}
+		MakeMove(g, m)
+	}
+	if r := Result(g); r != CHECKMATE {
+		t.Errorf("gameResult = %v, want %v", r, CHECKMATE)
+	}
+}
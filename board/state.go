@@ -0,0 +1,60 @@
+package board
+
+import (
+	"fmt"
+)
+
+// CastlingRights holds which of the four castling moves are still available, one bit each.
+type CastlingRights uint8
+
+// A GameState is a position, with everything FEN records, and the moves that led to it.
+type GameState struct{
+	B Board              // piece placement
+	PL int               // player whose turn it is                      ([PL]ayer)
+	CR CastlingRights    // castling moves that are still available       ([C]astling [R]ights)
+	EF int               // file of the en passant target square, or 0    ([E]n passant [F]ile)
+	ER int               // rank of the en passant target square, or 0    ([E]n passant [R]ank)
+	HC int               // moves since the last capture or pawn move     ([H]alfmove [C]lock)
+	FN int               // number of the current full move, from 1       ([F]ullmove [N]umber)
+	SP string            // FEN of the position the history starts from   ([S]tarting [P]osition)
+	H []HistoryEntry     // moves played since SP, oldest first           ([H]istory)
+}
+
+const (
+	WHITE_KINGSIDE CastlingRights  = 0b0001
+	WHITE_QUEENSIDE CastlingRights = 0b0010
+	BLACK_KINGSIDE CastlingRights  = 0b0100
+	BLACK_QUEENSIDE CastlingRights = 0b1000
+
+	NO_CASTLING CastlingRights  = 0b0000
+	ALL_CASTLING CastlingRights = 0b1111
+)
+
+// Castling rights lost when a move starts or ends on each square: the kings' and rooks' home squares.
+var CASTLING_LOST = [64]CastlingRights{
+	0  : WHITE_QUEENSIDE,                   // A1
+	4  : WHITE_KINGSIDE | WHITE_QUEENSIDE,  // E1
+	7  : WHITE_KINGSIDE,                    // H1
+	56 : BLACK_QUEENSIDE,                   // A8
+	60 : BLACK_KINGSIDE | BLACK_QUEENSIDE,  // E8
+	63 : BLACK_KINGSIDE}                    // H8
+
+// Copy returns a copy of g that moves can be made on and taken back without changing g.
+func (g *GameState) Copy() *GameState {
+	newG := *g
+	newG.H = g.H[:len(g.H):len(g.H)]  // appending to the copy's history must not write into ours
+	return &newG
+}
+
+// SquareName names (file, rank) in lower case, e.g. e4.
+func SquareName(file int, rank int) string {
+	return fmt.Sprintf("%c%d", rune(file - 'A' + 'a'), rank)
+}
+
+// ParseSquare returns the file and rank of a square named like e4.
+func ParseSquare(s string) (int, int, error) {
+	if (len(s) != 2) || (s[0] < 'a') || (s[0] > 'h') || (s[1] < '1') || (s[1] > '8') {
+		return 0, 0, fmt.Errorf("Invalid square %q.", s)
+	}
+	return int(s[0] - 'a') + 'A', int(s[1] - '0'), nil
+}
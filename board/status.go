@@ -0,0 +1,131 @@
+package board
+
+// A GameResult says whether the game is over and why.
+type GameResult int
+
+const (
+	IN_PROGRESS GameResult = iota
+
+	// decisive, against the player to move
+	CHECKMATE
+	RESIGNATION
+
+	// drawn automatically
+	STALEMATE
+	INSUFFICIENT_MATERIAL
+	FIVEFOLD_REPETITION
+	SEVENTY_FIVE_MOVE_RULE
+
+	// drawn only if a player claims it
+	THREEFOLD_REPETITION
+	FIFTY_MOVE_RULE
+)
+
+var RESULT_NAMES = map[GameResult]string{
+	IN_PROGRESS            : "in progress",
+	CHECKMATE              : "checkmate",
+	RESIGNATION            : "resignation",
+	STALEMATE              : "stalemate",
+	INSUFFICIENT_MATERIAL  : "insufficient material",
+	FIVEFOLD_REPETITION    : "fivefold repetition",
+	SEVENTY_FIVE_MOVE_RULE : "the 75-move rule",
+	THREEFOLD_REPETITION   : "threefold repetition",
+	FIFTY_MOVE_RULE        : "the 50-move rule"}
+
+// PLAYER_NAMES[p] is what player p is called in messages.
+var PLAYER_NAMES = [...]string{"White", "Black"}
+
+// String describes the result, e.g. "checkmate" or "the 50-move rule".
+func (r GameResult) String() string {
+	return RESULT_NAMES[r]
+}
+
+// IsDraw reports whether the result is a draw of any kind.
+func (r GameResult) IsDraw() bool {
+	return r >= STALEMATE
+}
+
+// Result is how the game stands for the player to move, counting only results that end the game without
+// anyone claiming them. Draws a player may claim are reported by ClaimableDraw.
+func Result(g *GameState) GameResult {
+	if CheckNoLegalMoves(g) {
+		if CheckForCheck(g, g.PL) {
+			return CHECKMATE
+		}
+		return STALEMATE
+	}
+	if InsufficientMaterial(g) {
+		return INSUFFICIENT_MATERIAL
+	}
+	if g.HC >= 150 {
+		return SEVENTY_FIVE_MOVE_RULE
+	}
+	if Repetitions(g) >= 5 {
+		return FIVEFOLD_REPETITION
+	}
+	return IN_PROGRESS
+}
+
+// ClaimableDraw is the draw the player to move could claim right now, or IN_PROGRESS if there is none.
+func ClaimableDraw(g *GameState) GameResult {
+	if Repetitions(g) >= 3 {
+		return THREEFOLD_REPETITION
+	}
+	if g.HC >= 100 {
+		return FIFTY_MOVE_RULE
+	}
+	return IN_PROGRESS
+}
+
+// InsufficientMaterial reports dead positions that no sequence of legal moves can turn into checkmate: king
+// against king with at most one knight or bishop on the board, or any number of bishops that all stand on
+// squares of one colour.
+func InsufficientMaterial(g *GameState) bool {
+	b := &g.B
+	for _, p := range []int{0, 1} {
+		if b.BB[p][PAWN] | b.BB[p][ROOK] | b.BB[p][QUEEN] != 0 {
+			return false
+		}
+	}
+	knights := PopCount(b.BB[0][KNIGHT] | b.BB[1][KNIGHT])
+	bishops := b.BB[0][BISHOP] | b.BB[1][BISHOP]
+	if knights + PopCount(bishops) <= 1 {
+		return true
+	}
+	return (knights == 0) && ((bishops & LIGHT_SQUARES == 0) || (bishops &^ LIGHT_SQUARES == 0))
+}
+
+// Repetitions is how many times the current position has occurred in the game, including now.
+func Repetitions(g *GameState) int {
+	// Positions only repeat if the same player is to move, with the same pieces on the same squares and the
+	// same castling and en passant captures available, which is just what the Zobrist key covers, and there
+	// is no need to look back past the last capture or pawn move.
+	key := g.Key()
+	count := 1
+	for plies := 1; plies <= g.HC; plies++ {
+		i := len(g.H) - 1 - plies
+		if i < -1 {
+			break
+		}
+		earlier := uint64(0)
+		if i >= 0 {
+			earlier = g.H[i].K
+		} else if start, err := LoadFEN(g.SP); err == nil {
+			earlier = start.Key()
+		}
+		if earlier == key {
+			count += 1
+		}
+	}
+	return count
+}
+
+// HasEnPassantCapture reports whether the player to move can legally take en passant.
+func HasEnPassantCapture(g *GameState) bool {
+	for _, m := range GenerateMoves(g, CAPTURES) {
+		if IsEnPassant(g, m) {
+			return true
+		}
+	}
+	return false
+}
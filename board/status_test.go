@@ -0,0 +1,86 @@
+package board
+
+import (
+	"testing"
+)
+
+func TestGameResult(t *testing.T) {
+	cases := []struct{
+		fen string
+		result GameResult
+	}{
+		{START_FEN, IN_PROGRESS},
+		{"rnb1kbnr/pppp1ppp/8/4p3/6Pq/5P2/PPPPP2P/RNBQKBNR w KQkq - 1 3", CHECKMATE},
+		{"7k/5Q2/6K1/8/8/8/8/8 b - - 0 1", STALEMATE},
+		{"8/8/4k3/8/8/3K4/8/8 w - - 0 1", INSUFFICIENT_MATERIAL},
+		{"8/8/4k3/8/8/3KN3/8/8 w - - 0 1", INSUFFICIENT_MATERIAL},
+		{"8/2b5/4k3/8/8/3KB3/8/8 w - - 0 1", INSUFFICIENT_MATERIAL},
+		{"8/3b4/4k3/8/8/3KB3/8/8 w - - 0 1", IN_PROGRESS},
+		{"8/8/4k3/8/8/2NKN3/8/8 w - - 0 1", IN_PROGRESS},
+		{"8/8/4k3/8/8/3K4/4P3/8 w - - 0 1", IN_PROGRESS},
+		{"8/8/4k3/8/8/3K4/8/R7 w - - 149 120", IN_PROGRESS},
+		{"8/8/4k3/8/8/3K4/8/R7 w - - 150 120", SEVENTY_FIVE_MOVE_RULE},
+		{"4k3/4Q3/4K3/8/8/8/8/8 b - - 150 120", CHECKMATE},
+	}
+	for _, c := range cases {
+		g := mustLoadFEN(t, c.fen)
+		if r := Result(g); r != c.result {
+			t.Errorf("Result(%s) = %v, want %v", c.fen, r, c.result)
+		}
+	}
+}
+
+func TestRepetition(t *testing.T) {
+	g := mustLoadFEN(t, START_FEN)
+	shuffle := MoveSequence{
+		Move{SF: 'G', SR: 1, DF: 'F', DR: 3, P: WHITE_KNIGHT},
+		Move{SF: 'G', SR: 8, DF: 'F', DR: 6, P: BLACK_KNIGHT},
+		Move{SF: 'F', SR: 3, DF: 'G', DR: 1, P: WHITE_KNIGHT},
+		Move{SF: 'F', SR: 6, DF: 'G', DR: 8, P: BLACK_KNIGHT}}
+	for i := 1; i <= 4; i++ {
+		for _, m := range shuffle {
+			MakeMove(g, m)
+		}
+		want := IN_PROGRESS
+		if i >= 2 {
+			want = THREEFOLD_REPETITION
+		}
+		if r := ClaimableDraw(g); r != want {
+			t.Errorf("after %d shuffles claimableDraw = %v, want %v", i, r, want)
+		}
+		want = IN_PROGRESS
+		if i >= 4 {
+			want = FIVEFOLD_REPETITION
+		}
+		if r := Result(g); r != want {
+			t.Errorf("after %d shuffles gameResult = %v, want %v", i, r, want)
+		}
+	}
+}
+
+func TestRepetitionIgnoresUnusableEnPassant(t *testing.T) {
+	// After 1. e4 the e3 square is open to en passant, but no black pawn can use it, so the position after
+	// 1. e4 Nf6 2. Nf3 Ng8 3. Ng1 Nf6 4. ... is the same as the one after 1. e4.
+	g := mustLoadFEN(t, START_FEN)
+	MakeMove(g, Move{SF: 'E', SR: 2, DF: 'E', DR: 4, P: WHITE_PAWN})
+	for i := 0; i < 2; i++ {
+		MakeMove(g, Move{SF: 'G', SR: 8, DF: 'F', DR: 6, P: BLACK_KNIGHT})
+		MakeMove(g, Move{SF: 'G', SR: 1, DF: 'F', DR: 3, P: WHITE_KNIGHT})
+		MakeMove(g, Move{SF: 'F', SR: 6, DF: 'G', DR: 8, P: BLACK_KNIGHT})
+		MakeMove(g, Move{SF: 'F', SR: 3, DF: 'G', DR: 1, P: WHITE_KNIGHT})
+	}
+	if n := Repetitions(g); n != 3 {
+		t.Errorf("repetitions = %d, want 3", n)
+	}
+}
+
+func TestFiftyMoveClaim(t *testing.T) {
+	g := mustLoadFEN(t, "8/8/4k3/8/8/3K4/8/R7 w - - 99 80")
+	if r := ClaimableDraw(g); r != IN_PROGRESS {
+		t.Errorf("claimableDraw at 99 plies = %v", r)
+	}
+	MakeMove(g, Move{SF: 'A', SR: 1, DF: 'A', DR: 2, P: WHITE_ROOK})
+	if r := ClaimableDraw(g); r != FIFTY_MOVE_RULE {
+		t.Errorf("claimableDraw at 100 plies = %v, want %v", r, FIFTY_MOVE_RULE)
+	}
+}
@@ -0,0 +1,62 @@
+package board
+
+// Zobrist hashing (https://www.chessprogramming.org/Zobrist_Hashing): every piece on every square, black to
+// move, each castling right and each en passant file gets a random 64-bit number, and a position's key is
+// the XOR of the numbers for everything true of it. Moving a piece then only takes two XORs, and equal
+// positions get equal keys.
+var ZOBRIST_PIECES [2][7][64]uint64
+var ZOBRIST_BLACK_TO_MOVE uint64
+var ZOBRIST_CASTLING [16]uint64   // indexed by CastlingRights, so any combination of rights is one lookup
+var ZOBRIST_EN_PASSANT [8]uint64  // indexed by file, A = 0
+
+func zobristPiece(p Piece, sq int) uint64 {
+	return ZOBRIST_PIECES[PlayerOf(p)][p & TYPE_MASK][sq]
+}
+
+// Key is the position's Zobrist key: equal for positions with the same pieces on the same squares, the
+// same player to move and the same castling rights and en passant captures, and almost surely different
+// otherwise.
+func (g *GameState) Key() uint64 {
+	// The placement part is kept up to date by Board.put and Board.remove as moves are made and taken back;
+	// the rest is a few lookups.
+	k := g.B.K ^ ZOBRIST_CASTLING[g.CR]
+	if g.PL == 1 {
+		k ^= ZOBRIST_BLACK_TO_MOVE
+	}
+	// Like castling rights, an en passant square only makes the position different if it can be used.
+	if (g.EF != 0) && (PAWN_ATTACKS[1 - g.PL][Square(g.EF, g.ER)] & g.B.BB[g.PL][PAWN] != 0) && HasEnPassantCapture(g) {
+		k ^= ZOBRIST_EN_PASSANT[g.EF - 'A']
+	}
+	return k
+}
+
+func init() {
+	// xorshift64*, from a fixed seed so keys are the same from run to run (and can be saved to disk)
+	state := uint64(0x9E3779B97F4A7C15)
+	random := func() uint64 {
+		state ^= state >> 12
+		state ^= state << 25
+		state ^= state >> 27
+		return state * 0x2545F4914F6CDD1D
+	}
+
+	for p := 0; p < 2; p++ {
+		for kind := PAWN; kind <= KING; kind++ {
+			for sq := 0; sq < 64; sq++ {
+				ZOBRIST_PIECES[p][kind][sq] = random()
+			}
+		}
+	}
+	ZOBRIST_BLACK_TO_MOVE = random()
+	rights := [...]uint64{random(), random(), random(), random()}
+	for cr := 0; cr < 16; cr++ {
+		for i, k := range rights {
+			if cr & (1 << uint(i)) != 0 {
+				ZOBRIST_CASTLING[cr] ^= k
+			}
+		}
+	}
+	for file := 0; file < 8; file++ {
+		ZOBRIST_EN_PASSANT[file] = random()
+	}
+}
@@ -0,0 +1,69 @@
+package board
+
+import (
+	"testing"
+)
+
+func TestKeyFollowsMoves(t *testing.T) {
+	// Walk two plies deep from each perft position, checking the key kept up by PlayMove and TakeBack
+	// against one computed from scratch.
+	for _, c := range PERFT_SUITE {
+		g := mustLoadFEN(t, c.fen)
+		start := g.Key()
+		for _, m := range LegalMoves(g) {
+			MakeMove(g, m)
+			for _, reply := range LegalMoves(g) {
+				MakeMove(g, reply)
+				if fresh := mustLoadFEN(t, g.FEN()); g.Key() != fresh.Key() {
+					t.Fatalf("%s: key after %v %v differs from the key of %s", c.name, m, reply, g.FEN())
+				}
+				UndoMove(g)
+			}
+			UndoMove(g)
+		}
+		if g.Key() != start {
+			t.Errorf("%s: key changed after making and undoing every move", c.name)
+		}
+	}
+}
+
+func TestKeyTranspositions(t *testing.T) {
+	// 1. Nf3 Nf6 2. Nc3 and 1. Nc3 Nf6 2. Nf3
+	orders := [][][4]int{
+		{{'G', 1, 'F', 3}, {'G', 8, 'F', 6}, {'B', 1, 'C', 3}},
+		{{'B', 1, 'C', 3}, {'G', 8, 'F', 6}, {'G', 1, 'F', 3}},
+	}
+	keys := []uint64{}
+	for _, order := range orders {
+		g := mustLoadFEN(t, START_FEN)
+		for _, s := range order {
+			m, _ := findMove(g, s[0], s[1], s[2], s[3])
+			MakeMove(g, m)
+		}
+		keys = append(keys, g.Key())
+	}
+	if keys[0] != keys[1] {
+		t.Errorf("same position reached by different move orders has different keys")
+	}
+
+	cases := []struct{
+		fen, other string
+		same bool
+	}{
+		{START_FEN, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR b KQkq - 0 1", false},
+		{START_FEN, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w Kkq - 0 1", false},
+		{START_FEN, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 12 40", true},
+		// no black pawn can take on e3, so the en passant square changes nothing
+		{"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+			"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1", true},
+		{"rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+			"rnbqkbnr/ppp1pppp/8/8/3pP3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1", false},
+		// the d4 pawn could take on c3, but that would leave its king in check
+		{"8/8/8/8/k1Pp3Q/8/8/4K3 b - c3 0 1", "8/8/8/8/k1Pp3Q/8/8/4K3 b - - 0 1", true},
+	}
+	for _, c := range cases {
+		if same := mustLoadFEN(t, c.fen).Key() == mustLoadFEN(t, c.other).Key(); same != c.same {
+			t.Errorf("%s and %s: same key = %v, want %v", c.fen, c.other, same, c.same)
+		}
+	}
+}
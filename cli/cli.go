@@ -0,0 +1,221 @@
+// Package cli plays chess in a terminal: the board is printed as text and moves are typed in, so it needs
+// no window and works over SSH or in a pipe.
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"chess/board"
+	"chess/engine"
+)
+
+// Unicode chess symbols, drawn as the FEN letters in ASCII mode.
+var UNICODE_PIECES = map[board.Piece]string{
+	board.WHITE_PAWN   : "♙",
+	board.WHITE_KNIGHT : "♘",
+	board.WHITE_BISHOP : "♗",
+	board.WHITE_ROOK   : "♖",
+	board.WHITE_QUEEN  : "♕",
+	board.WHITE_KING   : "♔",
+	board.BLACK_PAWN   : "♟",
+	board.BLACK_KNIGHT : "♞",
+	board.BLACK_BISHOP : "♝",
+	board.BLACK_ROOK   : "♜",
+	board.BLACK_QUEEN  : "♛",
+	board.BLACK_KING   : "♚"}
+
+const HELP = `Type a move as SAN (Nf3, exd5, O-O, e8=Q) or coordinates (g1f3, e7e8q), or a command:
+  moves    list the legal moves
+  undo     take back the last move
+  fen      show the position as FEN
+  pgn      show the game so far as PGN
+  flip     turn the board around
+  draw     claim a draw by repetition or the 50-move rule
+  resign   give up the game
+  help     show this again
+  quit     leave
+`
+
+// A Session is one game in the terminal.
+type Session struct{
+	G *board.GameState    // the game being played                       ([G]ame)
+	U bool                // draw pieces as Unicode symbols, not letters ([U]nicode)
+	F bool                // black at the bottom of the board            ([F]lipped)
+	R board.GameResult    // how the game ended, or IN_PROGRESS          ([R]esult)
+	C [2]bool             // which players the computer moves for        ([C]omputer)
+	L engine.Limits       // how long the computer thinks, 1s if unset   ([L]imits)
+
+	searcher *engine.Searcher
+}
+
+// Run plays the session's game, reading moves and commands from in and writing the board and messages to
+// out, with the computer answering for the players in C, until the game ends or in runs out.
+func (s *Session) Run(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	s.printBoard(out)
+	// the game may be over before it starts, if it was set up from a finished position
+	s.R = board.Result(s.G)
+	if s.R != board.IN_PROGRESS {
+		s.printResult(out)
+		return nil
+	}
+	for {
+		if s.C[s.G.PL] {
+			s.computerMove(out)
+			if s.R != board.IN_PROGRESS {
+				s.printResult(out)
+				return nil
+			}
+			continue
+		}
+		fmt.Fprintf(out, "%s to move: ", board.PLAYER_NAMES[s.G.PL])
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if done := s.handle(line, out); done {
+			return nil
+		}
+		if s.R != board.IN_PROGRESS {
+			s.printResult(out)
+			return nil
+		}
+	}
+}
+
+func (s *Session) handle(line string, out io.Writer) bool {
+	// Carry out one line of input; true means the player wants to leave.
+	switch strings.ToLower(line) {
+	case "quit", "exit":
+		return true
+	case "help", "?":
+		fmt.Fprint(out, HELP)
+	case "moves":
+		names := make([]string, 0)
+		for _, m := range board.LegalMoves(s.G) {
+			names = append(names, s.G.SAN(m))
+		}
+		fmt.Fprintln(out, strings.Join(names, " "))
+	case "undo":
+		if err := board.UndoMove(s.G); err != nil {
+			fmt.Fprintln(out, err)
+		} else {
+			// against the computer, take back its answer too, or it would just play it again
+			if s.C[s.G.PL] && !s.C[1 - s.G.PL] && (len(s.G.H) > 0) {
+				board.UndoMove(s.G)
+			}
+			s.printBoard(out)
+		}
+	case "fen":
+		fmt.Fprintln(out, s.G.FEN())
+	case "pgn":
+		game := board.NewPGNGame(s.G, board.PGNResult(s.G, s.R), "?", "?", time.Now())
+		board.WritePGN(out, game)
+	case "flip":
+		s.F = !s.F
+		s.printBoard(out)
+	case "draw":
+		if claim := board.ClaimableDraw(s.G); claim != board.IN_PROGRESS {
+			s.R = claim
+		} else {
+			fmt.Fprintln(out, "No draw to claim.")
+		}
+	case "resign":
+		s.R = board.RESIGNATION
+	default:
+		m, err := board.ParseMove(s.G, line)
+		if err != nil {
+			fmt.Fprintln(out, err)
+			return false
+		}
+		s.play(m, out)
+	}
+	return false
+}
+
+func (s *Session) play(m board.Move, out io.Writer) {
+	fmt.Fprintf(out, "%s played %s\n\n", board.PLAYER_NAMES[s.G.PL], s.G.SAN(m))
+	board.MakeMove(s.G, m)
+	s.printBoard(out)
+	s.R = board.Result(s.G)
+}
+
+func (s *Session) computerMove(out io.Writer) {
+	// The same searcher plays every move, so what it learns carries over.
+	if s.searcher == nil {
+		s.searcher = engine.NewSearcher()
+	}
+	limits := s.L
+	if limits == (engine.Limits{}) {
+		limits.MT = time.Second
+	}
+	m, _, ok := s.searcher.Search(s.G, limits)
+	if !ok {
+		// only happens in a finished game, which Result has already caught
+		s.R = board.Result(s.G)
+		return
+	}
+	s.play(m, out)
+}
+
+func (s *Session) printResult(out io.Writer) {
+	// Checkmate and resignation both lose the game for the player to move.
+	if s.R.IsDraw() {
+		fmt.Fprintf(out, "Game Over! Draw by %v.\n", s.R)
+	} else {
+		fmt.Fprintf(out, "Game Over! %s wins by %v!\n", board.PLAYER_NAMES[1 - s.G.PL], s.R)
+	}
+}
+
+func (s *Session) printBoard(out io.Writer) {
+	// Rank 8 at the top, as white sees it, unless flipped.
+	var sb strings.Builder
+	for row := 0; row < 8; row++ {
+		rank := 8 - row
+		if s.F {
+			rank = row + 1
+		}
+		fmt.Fprintf(&sb, "%d ", rank)
+		for col := 0; col < 8; col++ {
+			file := 'A' + col
+			if s.F {
+				file = 'H' - col
+			}
+			sb.WriteString(" " + s.pieceSymbol(s.G.B.At(file, rank)))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n  ")
+	for col := 0; col < 8; col++ {
+		file := 'a' + col
+		if s.F {
+			file = 'h' - col
+		}
+		fmt.Fprintf(&sb, " %c", rune(file))
+	}
+	sb.WriteString("\n\n")
+	if board.CheckForCheck(s.G, s.G.PL) {
+		sb.WriteString("Check!\n")
+	}
+	fmt.Fprint(out, sb.String())
+}
+
+func (s *Session) pieceSymbol(p board.Piece) string {
+	switch {
+	case p == board.EMPTY_SQUARE && s.U:
+		return "·"
+	case p == board.EMPTY_SQUARE:
+		return "."
+	case s.U:
+		return UNICODE_PIECES[p]
+	}
+	return string(board.FENChar(p))
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"chess/board"
+	"chess/engine"
+)
+
+func play(t *testing.T, fen string, input string, unicode bool) string {
+	t.Helper()
+	g, err := board.LoadFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	s := &Session{G: g, U: unicode}
+	if err := s.Run(strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestRun(t *testing.T) {
+	out := play(t, board.START_FEN, "e4\nf6\nKe3\nd4\nundo\nd4\ng5\nfen\nQh5#\ne5\n", false)
+	for _, want := range []string{
+		"8  r n b q k b n r\n7  p p p p p p p p\n",
+		"1  R N B Q K B N R\n\n   a b c d e f g h\n",
+		"White played e4\n",
+		`Illegal move "Ke3".`,
+		"rnbqkbnr/ppppp2p/5p2/6p1/3PP3/8/PPP2PPP/RNBQKBNR w KQkq g6 0 3\n",
+		"White played Qh5#\n",
+		"Game Over! White wins by checkmate!\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "played e5") {
+		t.Errorf("kept reading moves after the game ended")
+	}
+}
+
+func TestCommands(t *testing.T) {
+	out := play(t, "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1", "flip\nmoves\nresign\n", true)
+	for _, want := range []string{
+		"1  · · · ♔ · · · ·\n",
+		"\n   h g f e d c b a\n",
+		"Kd1 Kf1 Kd2 Kf2 e3 e4\n",
+		"Game Over! Black wins by resignation!\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out = play(t, "7k/6Q1/5K2/8/8/8/8/8 b - - 0 1", "Kh7\n", false)
+	if !strings.Contains(out, "Game Over! White wins by checkmate!\n") || strings.Contains(out, "Illegal move") {
+		t.Errorf("game set up checkmated didn't end:\n%s", out)
+	}
+
+	out = play(t, board.START_FEN, "Nf3\nNf6\nNg1\nNg8\nNf3\nNf6\nNg1\nNg8\ndraw\n", false)
+	if !strings.Contains(out, "Game Over! Draw by threefold repetition.\n") {
+		t.Errorf("draw claim not accepted:\n%s", out)
+	}
+}
+
+func TestComputer(t *testing.T) {
+	g, _ := board.NewGame()
+	s := &Session{G: g, C: [2]bool{false, true}, L: engine.Limits{D: 2}}
+	var out strings.Builder
+	if err := s.Run(strings.NewReader("e4\nundo\nfen\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"White played e4\n", "Black played ", board.START_FEN + "\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	// the computer mates as white, from the first move
+	g, _ = board.LoadFEN("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	s = &Session{G: g, C: [2]bool{true, false}, L: engine.Limits{D: 3}}
+	out.Reset()
+	if err := s.Run(strings.NewReader(""), &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "White played Ra8#\n") || (s.R != board.CHECKMATE) {
+		t.Errorf("computer didn't mate:\n%s", out.String())
+	}
+}
@@ -0,0 +1,47 @@
+// Command chess-cli plays chess in the terminal, or with -uci runs the engine for a chess GUI. Unlike the
+// main chess command it doesn't link SDL, so it builds and runs on machines without it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"chess/board"
+	"chess/cli"
+	"chess/engine"
+	"chess/uci"
+)
+
+func main() {
+	fen := flag.String("fen", board.START_FEN, "position to start from, in FEN")
+	unicode := flag.Bool("unicode", false, "draw pieces as Unicode chess symbols rather than letters")
+	engineMode := flag.Bool("uci", false, "run as a UCI engine on stdin and stdout instead")
+	computerFlags := engine.AddComputerFlags(flag.CommandLine)
+	flag.Parse()
+
+	computer, limits, err := computerFlags.Apply()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	if *engineMode {
+		if err := uci.Run(os.Stdin, os.Stdout); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	g, err := board.LoadFEN(*fen)
+	if err != nil {
+		fmt.Println("Board is broken:", err)
+		os.Exit(1)
+	}
+	s := &cli.Session{G: g, U: *unicode, C: computer, L: limits}
+	if err := s.Run(os.Stdin, os.Stdout); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,76 @@
+// Command tune fits the engine's evaluation weights to the results of games, from files of labelled
+// positions in EPD or FEN (see engine.ParseLabelledPosition), and writes them out as a parameters file for
+// the -params flag or the EvalFile UCI option.
+//
+//	tune -out params.txt quiet-labeled.epd
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"chess/engine"
+)
+
+func main() {
+	start := flag.String("params", "", "weights to start from (default the built-in ones)")
+	out := flag.String("out", "params.txt", "file to write the tuned weights to, after every pass")
+	workers := flag.Int("workers", runtime.NumCPU(), "goroutines to share the work between")
+	passes := flag.Int("passes", 0, "stop after this many passes, or 0 to go on until nothing improves")
+	step := flag.Int("step", 1, "how far to move a weight at a time")
+	k := flag.Float64("k", 0, "steepness of the logistic curve, or 0 to fit it to the starting weights")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] positions.epd ...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	params := &engine.DEFAULT_PARAMS
+	if *start != "" {
+		var err error
+		if params, err = engine.LoadParams(*start); err != nil {
+			fmt.Println("Could not load the starting weights:", err)
+			os.Exit(1)
+		}
+	}
+
+	t := &engine.Tuner{W: *workers, K: *k}
+	for _, path := range flag.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		positions, err := engine.ReadLabelledPositions(f)
+		f.Close()
+		if err != nil {
+			fmt.Printf("%s: %v\n", path, err)
+			os.Exit(1)
+		}
+		t.P = append(t.P, positions...)
+	}
+	fmt.Println("Positions:", len(t.P))
+
+	if t.K == 0 {
+		fmt.Println("K:", t.FitK(params))
+	}
+	fmt.Println("Error:", t.Error(params))
+
+	began := time.Now()
+	t.OnPass = func(pass int, p *engine.Params, e float64) {
+		fmt.Printf("Pass %d: error %.8f after %v\n", pass, e, time.Since(began).Round(time.Second))
+		if err := engine.SaveParams(*out, p); err != nil {
+			fmt.Println("Could not save the weights:", err)
+			os.Exit(1)
+		}
+	}
+	t.Tune(params, *step, *passes)
+	fmt.Println("Weights saved to", *out)
+}
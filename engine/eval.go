@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"chess/board"
+)
+
+// Piece values in centipawns, indexed by piece type, for ordering captures.
+var PIECE_VALUES = [7]int{0, 100, 320, 330, 500, 900, 0}
+
+// How much each piece counts towards the middlegame: with all of them on the board the phase is
+// MAX_PHASE, and pure middlegame weights apply; with none left, only the endgame weights do.
+var PHASE_WEIGHTS = [7]int{0, 0, 1, 1, 2, 4, 0}
+
+const MAX_PHASE = 24
+
+// PARAMS are the weights Evaluate uses.
+var PARAMS = &DEFAULT_PARAMS
+
+var fileMasks [8]board.Bitboard
+var adjacentFiles [8]board.Bitboard         // the files either side of each file
+var passedMasks [2][64]board.Bitboard       // squares that must hold no enemy pawn for a pawn here to be passed
+var frontSpans [2][64]board.Bitboard        // squares in front of a square, on its file
+var shieldMasks [2][64]board.Bitboard       // squares where pawns shelter a king here
+
+// Evaluate is the position's value in centipawns for the player to move, using PARAMS.
+func Evaluate(g *board.GameState) int {
+	return PARAMS.Evaluate(g)
+}
+
+// Evaluate is the position's value in centipawns for the player to move.
+func (p *Params) Evaluate(g *board.GameState) int {
+	var score [2]int
+	phase := 0
+	for player := 0; player < 2; player++ {
+		mg, eg := p.evaluateSide(g, player)
+		score[MG] += mg * (1 - 2 * player)
+		score[EG] += eg * (1 - 2 * player)
+		for kind := board.KNIGHT; kind <= board.QUEEN; kind++ {
+			phase += PHASE_WEIGHTS[kind] * board.PopCount(g.B.BB[player][kind])
+		}
+	}
+	if phase > MAX_PHASE {
+		// early promotions
+		phase = MAX_PHASE
+	}
+	blended := (score[MG] * phase + score[EG] * (MAX_PHASE - phase)) / MAX_PHASE
+	if g.PL == 1 {
+		return -blended
+	}
+	return blended
+}
+
+func (p *Params) evaluateSide(g *board.GameState, player int) (int, int) {
+	// The middlegame and endgame scores of everything that favours player.
+	var score [2]int
+	add := func(weights [2]int, count int) {
+		score[MG] += weights[MG] * count
+		score[EG] += weights[EG] * count
+	}
+	bb := &g.B.BB
+	enemy := 1 - player
+	occupied := g.B.Occupied()
+	ours := board.Bitboard(0)
+	for kind := board.PAWN; kind <= board.KING; kind++ {
+		ours |= bb[player][kind]
+	}
+	enemyPawnAttacks := board.Bitboard(0)
+	for pawns := bb[enemy][board.PAWN]; pawns != 0; {
+		enemyPawnAttacks |= board.PAWN_ATTACKS[enemy][board.PopLSB(&pawns)]
+	}
+	enemyKing := board.LSB(bb[enemy][board.KING])
+	kingZone := board.KING_ATTACKS[enemyKing] | board.Bit(enemyKing)
+
+	for kind := board.PAWN; kind <= board.KING; kind++ {
+		for pieces := bb[player][kind]; pieces != 0; {
+			sq := board.PopLSB(&pieces)
+			// the tables are drawn as white sees the board, a8 first
+			index := sq ^ 56
+			if player == 1 {
+				index = sq
+			}
+			score[MG] += p.MV[MG][kind] + p.PST[MG][kind][index]
+			score[EG] += p.MV[EG][kind] + p.PST[EG][kind][index]
+			if (kind == board.PAWN) || (kind == board.KING) {
+				continue
+			}
+			attacks := board.AttacksFrom(kind, sq, occupied)
+			add([2]int{p.MOB[MG][kind], p.MOB[EG][kind]}, board.PopCount(attacks &^ ours &^ enemyPawnAttacks))
+			add([2]int{p.KA[MG][kind], p.KA[EG][kind]}, board.PopCount(attacks & kingZone))
+		}
+	}
+
+	pawns := bb[player][board.PAWN]
+	for rest := pawns; rest != 0; {
+		sq := board.PopLSB(&rest)
+		file := sq % 8
+		if frontSpans[player][sq] & pawns != 0 {
+			add(p.DP, 1)
+		}
+		if adjacentFiles[file] & pawns == 0 {
+			add(p.IP, 1)
+		}
+		if passedMasks[player][sq] & bb[enemy][board.PAWN] == 0 {
+			rank := sq / 8
+			if player == 1 {
+				rank = 7 - rank
+			}
+			add([2]int{p.PP[MG][rank], p.PP[EG][rank]}, 1)
+		}
+	}
+
+	king := board.LSB(bb[player][board.KING])
+	add(p.KS, board.PopCount(shieldMasks[player][king] & pawns))
+	if board.PopCount(bb[player][board.BISHOP]) >= 2 {
+		add(p.BP, 1)
+	}
+	return score[MG], score[EG]
+}
+
+func init() {
+	for file := 0; file < 8; file++ {
+		fileMasks[file] = board.FILE_A << uint(file)
+	}
+	for file := 0; file < 8; file++ {
+		if file > 0 {
+			adjacentFiles[file] |= fileMasks[file - 1]
+		}
+		if file < 7 {
+			adjacentFiles[file] |= fileMasks[file + 1]
+		}
+	}
+	for sq := 0; sq < 64; sq++ {
+		file, rank := sq % 8, sq / 8
+		for r := 0; r < 8; r++ {
+			// white looks up the board, black down it
+			var ahead [2]bool
+			ahead[0], ahead[1] = r > rank, r < rank
+			for player := 0; player < 2; player++ {
+				if !ahead[player] {
+					continue
+				}
+				row := board.RANK_1 << uint(8 * r)
+				frontSpans[player][sq] |= row & fileMasks[file]
+				passedMasks[player][sq] |= row & (fileMasks[file] | adjacentFiles[file])
+				if (r - rank <= 2) && (rank - r <= 2) {
+					shieldMasks[player][sq] |= row & (fileMasks[file] | adjacentFiles[file])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"chess/board"
+)
+
+func mirrorFEN(fen string) string {
+	// The same position with the colours swapped and the board turned upside down.
+	fields := strings.Fields(fen)
+	ranks := strings.Split(fields[0], "/")
+	for i, j := 0, len(ranks) - 1; i < j; i, j = i + 1, j - 1 {
+		ranks[i], ranks[j] = ranks[j], ranks[i]
+	}
+	swapCase := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if (r >= 'a') && (r <= 'z') {
+				return r - 'a' + 'A'
+			} else if (r >= 'A') && (r <= 'Z') {
+				return r - 'A' + 'a'
+			}
+			return r
+		}, s)
+	}
+	fields[0] = swapCase(strings.Join(ranks, "/"))
+	fields[1] = map[string]string{"w": "b", "b": "w"}[fields[1]]
+	if fields[2] != "-" {
+		fields[2] = swapCase(fields[2])
+	}
+	if fields[3] != "-" {
+		fields[3] = fields[3][:1] + map[byte]string{'3': "6", '6': "3"}[fields[3][1]]
+	}
+	return strings.Join(fields, " ")
+}
+
+var EVAL_POSITIONS = []string{
+	board.START_FEN,
+	"r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1",
+	"8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1",
+	"r1bqkb1r/pppp1ppp/2n2n2/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR w KQkq - 4 4",
+	"6k1/5ppp/8/3P4/8/8/P4PPP/6K1 b - - 0 1",
+}
+
+func TestEvaluateIsSymmetric(t *testing.T) {
+	for _, fen := range EVAL_POSITIONS {
+		g := mustLoadFEN(t, fen)
+		mirrored := mustLoadFEN(t, mirrorFEN(fen))
+		if a, b := Evaluate(g), Evaluate(mirrored); a != b {
+			t.Errorf("%s scores %d, but %s scores %d", fen, a, mirrorFEN(fen), b)
+		}
+	}
+	if score := Evaluate(mustLoadFEN(t, board.START_FEN)); score != 0 {
+		t.Errorf("start position scores %d", score)
+	}
+}
+
+func TestEvaluateTerms(t *testing.T) {
+	better := func(good string, bad string, why string) {
+		t.Helper()
+		if a, b := Evaluate(mustLoadFEN(t, good)), Evaluate(mustLoadFEN(t, bad)); a <= b {
+			t.Errorf("%s: %s scores %d, no more than %s at %d", why, good, a, bad, b)
+		}
+	}
+	better("4k3/8/8/8/8/8/3PP3/4K3 w - - 0 1", "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1", "an extra pawn")
+	better("4k3/8/8/8/8/8/3P1P2/4K3 w - - 0 1", "4k3/8/8/8/8/4P3/4P3/4K3 w - - 0 1", "doubled pawns")
+	better("4k3/8/8/8/8/8/3PP3/4K3 w - - 0 1", "4k3/8/8/8/8/8/2P1P3/4K3 w - - 0 1", "isolated pawns")
+	better("4k3/p7/8/3P4/8/8/8/4K3 w - - 0 1", "4k3/2p5/8/3P4/8/8/8/4K3 w - - 0 1", "a passed pawn")
+	better("4k3/8/8/8/8/8/8/2BBK3 w - - 0 1", "4k3/8/8/8/8/8/8/2BNK3 w - - 0 1", "the bishop pair")
+}
+
+func TestParamsRoundTrip(t *testing.T) {
+	p := DEFAULT_PARAMS
+	p.PST[EG][board.KNIGHT][27] = -7
+	p.BP[MG] = 42
+	var sb strings.Builder
+	if err := WriteParams(&sb, &p); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadParams(strings.NewReader(sb.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *read != p {
+		t.Errorf("read back different parameters from\n%s", sb.String())
+	}
+
+	values := p.Values()
+	values[0] += 1
+	p.SetValues(values)
+	if p.MV[MG][board.PAWN] != DEFAULT_PARAMS.MV[MG][board.PAWN] + 1 {
+		t.Errorf("SetValues didn't set the pawn value")
+	}
+
+	read, err = ReadParams(strings.NewReader("# just the bishop pair\nbishoppair.mg 25\n"))
+	if (err != nil) || (read.BP[MG] != 25) || (read.MV != DEFAULT_PARAMS.MV) {
+		t.Errorf("partial file read as %v, %v", read, err)
+	}
+	for text, want := range map[string]string{
+		"material.mg 1 2 3\n": "Line 1: Term material.mg has 3 values, not 5.",
+		"shield.mg 1 2\n": "Line 1: Unexpected value 2.",
+		"\nqueenside.mg 1\n": "Line 2: Unknown term \"queenside.mg\".",
+	} {
+		if _, err := ReadParams(strings.NewReader(text)); (err == nil) || (err.Error() != want) {
+			t.Errorf("reading %q gave %v, want %s", text, err, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ComputerFlags are the command line flags that set up the computer player: which players it moves for, how
+// long it thinks and which evaluation weights it uses. The chess and chess-cli commands share them.
+type ComputerFlags struct{
+	side *string
+	moveTime *time.Duration
+	depth *int
+	params *string
+}
+
+// AddComputerFlags defines -computer, -movetime, -depth and -params on fs.
+func AddComputerFlags(fs *flag.FlagSet) *ComputerFlags {
+	return &ComputerFlags{
+		side: fs.String("computer", "", "let the computer play white, black or both"),
+		moveTime: fs.Duration("movetime", 2 * time.Second, "how long the computer thinks about each move"),
+		depth: fs.Int("depth", 0, "how many plies deep the computer looks, instead of -movetime"),
+		params: fs.String("params", "", "file of evaluation weights for the computer, as the tuner writes them"),
+	}
+}
+
+// Apply loads the weights -params names into PARAMS, if it names any, and returns which players the computer
+// moves for and the limits it thinks within.
+func (f *ComputerFlags) Apply() ([2]bool, Limits, error) {
+	if *f.params != "" {
+		params, err := LoadParams(*f.params)
+		if err != nil {
+			return [2]bool{}, Limits{}, fmt.Errorf("Could not load the evaluation weights: %v", err)
+		}
+		PARAMS = params
+	}
+	computer, err := ParseComputer(*f.side)
+	if err != nil {
+		return [2]bool{}, Limits{}, err
+	}
+	if *f.depth > 0 {
+		return computer, Limits{D: *f.depth}, nil
+	}
+	return computer, Limits{MT: *f.moveTime}, nil
+}
+
+// ParseComputer turns "white", "black", "both" or "" into which players the computer moves for.
+func ParseComputer(side string) ([2]bool, error) {
+	switch strings.ToLower(side) {
+	case "":
+		return [2]bool{false, false}, nil
+	case "white":
+		return [2]bool{true, false}, nil
+	case "black":
+		return [2]bool{false, true}, nil
+	case "both":
+		return [2]bool{true, true}, nil
+	}
+	return [2]bool{}, fmt.Errorf("Unknown side %q for the computer: use white, black or both.", side)
+}
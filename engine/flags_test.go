@@ -0,0 +1,25 @@
+package engine
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestComputerFlags(t *testing.T) {
+	fs := flag.NewFlagSet("chess", flag.ContinueOnError)
+	f := AddComputerFlags(fs)
+	if err := fs.Parse([]string{"-computer", "Black", "-depth", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	computer, limits, err := f.Apply()
+	if (err != nil) || (computer != [2]bool{false, true}) || (limits != Limits{D: 5}) {
+		t.Errorf("applied as %v, %+v, %v", computer, limits, err)
+	}
+
+	fs = flag.NewFlagSet("chess", flag.ContinueOnError)
+	f = AddComputerFlags(fs)
+	fs.Parse([]string{"-computer", "neither"})
+	if _, _, err := f.Apply(); (err == nil) || (err.Error() != `Unknown side "neither" for the computer: use white, black or both.`) {
+		t.Errorf("unknown side gave %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"chess/board"
+)
+
+// Move ordering: alpha-beta cuts off the most when the best move is searched first, so moves are tried in
+// order of how likely they are to be best.
+const (
+	TT_MOVE_SCORE = 1 << 30      // the best move the last time the position was searched
+	CAPTURE_SCORE = 1 << 28      // captures and queen promotions, most valuable victim, least valuable attacker first
+	KILLER_SCORE = 1 << 27       // quiet moves that caused a cutoff in a sibling position
+	MAX_HISTORY = 1 << 26        // quiet moves by how often they have caused cutoffs
+)
+
+func (s *Searcher) orderMoves(g *board.GameState, ply int, ttMove uint16) {
+	// Scores s.buffers[ply] into s.scores[ply], for pickMove.
+	moves := s.buffers[ply]
+	scores := s.scores[ply][:0]
+	for _, m := range moves {
+		packed := packMove(m)
+		moved := g.B.At(m.SF, m.SR)
+		victim := g.B.At(m.DF, m.DR) & board.TYPE_MASK
+		if board.IsEnPassant(g, m) {
+			victim = board.PAWN
+		}
+		score := 0
+		switch {
+		case packed == ttMove:
+			score = TT_MOVE_SCORE
+		case (victim != board.EMPTY_SQUARE) || (m.P & board.TYPE_MASK == board.QUEEN && moved & board.TYPE_MASK == board.PAWN):
+			score = CAPTURE_SCORE + PIECE_VALUES[victim] * 8 - int(moved & board.TYPE_MASK)
+			if m.P != moved {
+				score += PIECE_VALUES[m.P & board.TYPE_MASK]
+			}
+		case packed == s.killers[ply][0]:
+			score = KILLER_SCORE + 1
+		case packed == s.killers[ply][1]:
+			score = KILLER_SCORE
+		default:
+			score = s.history[g.PL][board.Square(m.SF, m.SR)][board.Square(m.DF, m.DR)]
+		}
+		scores = append(scores, score)
+	}
+	s.scores[ply] = scores
+}
+
+func (s *Searcher) pickMove(ply int, i int) board.Move {
+	// Swaps the best scored of the moves from i on into place i and returns it. Sorting lazily like this
+	// saves ordering the moves a cutoff means are never searched.
+	moves, scores := s.buffers[ply], s.scores[ply]
+	best := i
+	for j := i + 1; j < len(moves); j++ {
+		if scores[j] > scores[best] {
+			best = j
+		}
+	}
+	moves[i], moves[best] = moves[best], moves[i]
+	scores[i], scores[best] = scores[best], scores[i]
+	return moves[i]
+}
+
+func (s *Searcher) rememberQuietMove(player int, m board.Move, depth int, ply int) {
+	// A quiet move caused a cutoff: try it early in sibling positions (as a killer) and everywhere else.
+	packed := packMove(m)
+	if s.killers[ply][0] != packed {
+		s.killers[ply][1] = s.killers[ply][0]
+		s.killers[ply][0] = packed
+	}
+	h := &s.history[player][board.Square(m.SF, m.SR)][board.Square(m.DF, m.DR)]
+	*h += depth * depth
+	if *h > MAX_HISTORY {
+		s.ageHistory(2)
+	}
+}
+
+func (s *Searcher) ageHistory(divisor int) {
+	// Older cutoffs count for less, and the scores stay below KILLER_SCORE.
+	for p := range s.history {
+		for from := range s.history[p] {
+			for to := range s.history[p][from] {
+				s.history[p][from][to] /= divisor
+			}
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package engine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Game phases. Every evaluation term has a middlegame and an endgame weight, and a position is scored as a
+// blend of the two according to how much material is left (see PHASE_WEIGHTS).
+const (
+	MG = 0
+	EG = 1
+)
+
+// Params holds the evaluation weights, in centipawns for the player the term favours. Tables indexed by
+// piece type only use PAWN..QUEEN or KNIGHT..QUEEN; piece-square tables are laid out as white sees the board,
+// a8 first and h1 last, and are read mirrored for black.
+type Params struct{
+	MV [2][7]int            // material                                          ([M]aterial [V]alues)
+	PST [2][7][64]int       // piece-square tables                               ([P]iece-[S]quare [T]ables)
+	DP [2]int               // each pawn behind another of its colour            ([D]oubled [P]awn)
+	IP [2]int               // each pawn with no pawns of its colour beside it   ([I]solated [P]awn)
+	PP [2][8]int            // passed pawns, by rank counted from their side     ([P]assed [P]awn)
+	MOB [2][7]int           // each safe square a piece attacks                  ([MOB]ility)
+	KS [2]int               // each pawn sheltering the king                     ([K]ing [S]hield)
+	KA [2][7]int            // each attack on the squares around the enemy king  ([K]ing [A]ttack)
+	BP [2]int               // having both bishops                               ([B]ishop [P]air)
+}
+
+// A paramTerm is one named line of a parameters file, holding values of a Params.
+type paramTerm struct{
+	name string
+	values []int            // aliases the Params fields
+}
+
+var PHASE_NAMES = [2]string{"mg", "eg"}
+var PST_NAMES = [7]string{"", "pawn", "knight", "bishop", "rook", "queen", "king"}
+
+func (p *Params) terms() []paramTerm {
+	// Every tunable value, in the order the parameters file lists them.
+	terms := []paramTerm{}
+	for phase, suffix := range PHASE_NAMES {
+		terms = append(terms, paramTerm{"material." + suffix, p.MV[phase][1:6]})
+	}
+	for kind := 1; kind <= 6; kind++ {
+		for phase, suffix := range PHASE_NAMES {
+			terms = append(terms, paramTerm{"pst." + PST_NAMES[kind] + "." + suffix, p.PST[phase][kind][:]})
+		}
+	}
+	for phase, suffix := range PHASE_NAMES {
+		terms = append(terms,
+			paramTerm{"doubled." + suffix, p.DP[phase:phase + 1]},
+			paramTerm{"isolated." + suffix, p.IP[phase:phase + 1]},
+			paramTerm{"passed." + suffix, p.PP[phase][:]},
+			paramTerm{"mobility." + suffix, p.MOB[phase][2:6]},
+			paramTerm{"shield." + suffix, p.KS[phase:phase + 1]},
+			paramTerm{"kingattack." + suffix, p.KA[phase][2:6]},
+			paramTerm{"bishoppair." + suffix, p.BP[phase:phase + 1]})
+	}
+	return terms
+}
+
+// Values returns every weight in p as one slice, in file order, which is how the tuner sees them.
+// Changing the slice doesn't change p; SetValues does.
+func (p *Params) Values() []int {
+	values := []int{}
+	for _, term := range p.terms() {
+		values = append(values, term.values...)
+	}
+	return values
+}
+
+// SetValues sets every weight in p from a slice in the order Values returns them.
+func (p *Params) SetValues(values []int) {
+	for _, term := range p.terms() {
+		values = values[copy(term.values, values):]
+	}
+}
+
+// ReadParams reads parameters written by WriteParams. Each term is its name followed by its values, which
+// may run over several lines; terms left out keep their default values, and # starts a comment.
+func ReadParams(r io.Reader) (*Params, error) {
+	p := DEFAULT_PARAMS
+	terms := map[string][]int{}
+	for _, term := range p.terms() {
+		terms[term.name] = term.values
+	}
+
+	var values []int = nil
+	name, count := "", 0
+	finish := func() error {
+		if (name != "") && (count != len(values)) {
+			return fmt.Errorf("Term %s has %d values, not %d.", name, count, len(values))
+		}
+		return nil
+	}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line += 1
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		for _, field := range strings.Fields(text) {
+			n, err := strconv.Atoi(field)
+			if err == nil {
+				if (name == "") || (count == len(values)) {
+					return nil, fmt.Errorf("Line %d: Unexpected value %s.", line, field)
+				}
+				values[count] = n
+				count += 1
+				continue
+			}
+			if err := finish(); err != nil {
+				return nil, fmt.Errorf("Line %d: %v", line, err)
+			}
+			var known bool
+			if values, known = terms[field]; !known {
+				return nil, fmt.Errorf("Line %d: Unknown term %q.", line, field)
+			}
+			name, count = field, 0
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := finish(); err != nil {
+		return nil, fmt.Errorf("Line %d: %v", line, err)
+	}
+	return &p, nil
+}
+
+// WriteParams writes p in the format ReadParams reads, with the piece-square tables drawn as boards.
+func WriteParams(w io.Writer, p *Params) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# chess evaluation parameters, in centipawns; .mg is the middlegame weight and .eg the endgame")
+	for _, term := range p.terms() {
+		fmt.Fprint(bw, term.name)
+		for i, v := range term.values {
+			if (len(term.values) == 64) && (i % 8 == 0) {
+				fmt.Fprint(bw, "\n   ")
+			}
+			fmt.Fprintf(bw, " %4d", v)
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// LoadParams reads a parameters file.
+func LoadParams(path string) (*Params, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadParams(f)
+}
+
+// SaveParams writes p to a parameters file.
+func SaveParams(path string, p *Params) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteParams(f, p); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// DEFAULT_PARAMS are hand-picked starting weights, until tuned ones are loaded.
+var DEFAULT_PARAMS = Params{
+	MV: [2][7]int{
+		{0, 100, 320, 330, 500, 900, 0},
+		{0, 120, 300, 320, 530, 950, 0}},
+	PST: [2][7][64]int{
+		{{}, PAWN_MG, KNIGHT_TABLE, BISHOP_TABLE, ROOK_TABLE, QUEEN_TABLE, KING_MG},
+		{{}, PAWN_EG, KNIGHT_TABLE, BISHOP_TABLE, ROOK_TABLE, QUEEN_TABLE, KING_EG}},
+	DP: [2]int{-10, -20},
+	IP: [2]int{-10, -15},
+	PP: [2][8]int{
+		{0, 5, 10, 15, 25, 40, 70, 0},
+		{0, 10, 20, 35, 60, 100, 150, 0}},
+	MOB: [2][7]int{
+		{0, 0, 4, 5, 2, 1, 0},
+		{0, 0, 4, 5, 4, 2, 0}},
+	KS: [2]int{10, 0},
+	KA: [2][7]int{
+		{0, 0, 8, 8, 12, 20, 0},
+		{0, 0, 0, 0, 0, 0, 0}},
+	BP: [2]int{30, 50},
+}
+
+var PAWN_MG = [64]int{
+	 0,   0,   0,   0,   0,   0,   0,   0,
+	50,  50,  50,  50,  50,  50,  50,  50,
+	10,  10,  20,  30,  30,  20,  10,  10,
+	 5,   5,  10,  25,  25,  10,   5,   5,
+	 0,   0,   0,  20,  20,   0,   0,   0,
+	 5,  -5, -10,   0,   0, -10,  -5,   5,
+	 5,  10,  10, -20, -20,  10,  10,   5,
+	 0,   0,   0,   0,   0,   0,   0,   0}
+
+var PAWN_EG = [64]int{
+	 0,   0,   0,   0,   0,   0,   0,   0,
+	80,  80,  80,  80,  80,  80,  80,  80,
+	50,  50,  50,  50,  50,  50,  50,  50,
+	30,  30,  30,  30,  30,  30,  30,  30,
+	15,  15,  15,  15,  15,  15,  15,  15,
+	 5,   5,   5,   5,   5,   5,   5,   5,
+	 0,   0,   0,   0,   0,   0,   0,   0,
+	 0,   0,   0,   0,   0,   0,   0,   0}
+
+var KNIGHT_TABLE = [64]int{
+	-50, -40, -30, -30, -30, -30, -40, -50,
+	-40, -20,   0,   0,   0,   0, -20, -40,
+	-30,   0,  10,  15,  15,  10,   0, -30,
+	-30,   5,  15,  20,  20,  15,   5, -30,
+	-30,   0,  15,  20,  20,  15,   0, -30,
+	-30,   5,  10,  15,  15,  10,   5, -30,
+	-40, -20,   0,   5,   5,   0, -20, -40,
+	-50, -40, -30, -30, -30, -30, -40, -50}
+
+var BISHOP_TABLE = [64]int{
+	-20, -10, -10, -10, -10, -10, -10, -20,
+	-10,   0,   0,   0,   0,   0,   0, -10,
+	-10,   0,   5,  10,  10,   5,   0, -10,
+	-10,   5,   5,  10,  10,   5,   5, -10,
+	-10,   0,  10,  10,  10,  10,   0, -10,
+	-10,  10,  10,  10,  10,  10,  10, -10,
+	-10,   5,   0,   0,   0,   0,   5, -10,
+	-20, -10, -10, -10, -10, -10, -10, -20}
+
+var ROOK_TABLE = [64]int{
+	 0,   0,   0,   0,   0,   0,   0,   0,
+	 5,  10,  10,  10,  10,  10,  10,   5,
+	-5,   0,   0,   0,   0,   0,   0,  -5,
+	-5,   0,   0,   0,   0,   0,   0,  -5,
+	-5,   0,   0,   0,   0,   0,   0,  -5,
+	-5,   0,   0,   0,   0,   0,   0,  -5,
+	-5,   0,   0,   0,   0,   0,   0,  -5,
+	 0,   0,   0,   5,   5,   0,   0,   0}
+
+var QUEEN_TABLE = [64]int{
+	-20, -10, -10,  -5,  -5, -10, -10, -20,
+	-10,   0,   0,   0,   0,   0,   0, -10,
+	-10,   0,   5,   5,   5,   5,   0, -10,
+	 -5,   0,   5,   5,   5,   5,   0,  -5,
+	  0,   0,   5,   5,   5,   5,   0,  -5,
+	-10,   5,   5,   5,   5,   5,   0, -10,
+	-10,   0,   5,   0,   0,   0,   0, -10,
+	-20, -10, -10,  -5,  -5, -10, -10, -20}
+
+var KING_MG = [64]int{
+	-30, -40, -40, -50, -50, -40, -40, -30,
+	-30, -40, -40, -50, -50, -40, -40, -30,
+	-30, -40, -40, -50, -50, -40, -40, -30,
+	-30, -40, -40, -50, -50, -40, -40, -30,
+	-20, -30, -30, -40, -40, -30, -30, -20,
+	-10, -20, -20, -20, -20, -20, -20, -10,
+	 20,  20,   0,   0,   0,   0,  20,  20,
+	 20,  30,  10,   0,   0,  10,  30,  20}
+
+var KING_EG = [64]int{
+	-50, -40, -30, -20, -20, -30, -40, -50,
+	-30, -20, -10,   0,   0, -10, -20, -30,
+	-30, -10,  20,  30,  30,  20, -10, -30,
+	-30, -10,  30,  40,  40,  30, -10, -30,
+	-30, -10,  30,  40,  40,  30, -10, -30,
+	-30, -10,  20,  30,  30,  20, -10, -30,
+	-30, -30,   0,   0,   0,   0, -30, -30,
+	-50, -30, -30, -30, -30, -30, -30, -50}
@@ -0,0 +1,341 @@
+// Package engine plays chess: it searches the moves of a board.GameState for the best one.
+package engine
+
+import (
+	"sync/atomic"
+	"time"
+
+	"chess/board"
+)
+
+const (
+	INFINITY = 32000
+	MATE = 31000         // the score for giving checkmate now; mate in n plies scores MATE - n
+	MAX_PLY = 128
+)
+
+// Limits says how long a search may run. Zero values mean no limit of that kind, and a search with no
+// limits at all runs until it is stopped.
+type Limits struct{
+	D int                  // depth to search to, in plies                        ([D]epth)
+	N int                  // nodes to search                                     ([N]odes)
+	MT time.Duration       // time to spend on the move                           ([M]ove [T]ime)
+	T [2]time.Duration     // each player's time left on the clock                ([T]ime)
+	I [2]time.Duration     // each player's increment per move                    ([I]ncrement)
+	MTG int                // moves until the next time control, or 0 for none    ([M]oves [T]o [G]o)
+}
+
+// Info reports on a search after each depth it completes.
+type Info struct{
+	D int                  // depth completed                                     ([D]epth)
+	S int                  // score for the player to move, in centipawns         ([S]core)
+	N int                  // nodes searched so far                               ([N]odes)
+	T time.Duration        // time spent so far                                   ([T]ime)
+	PV board.MoveSequence  // the best line found                                 ([P]rincipal [V]ariation)
+}
+
+// MateIn is the number of moves to a forced mate for the player to move (negative if they are the one
+// getting mated), and false if the score isn't a mate score.
+func (info Info) MateIn() (int, bool) {
+	switch {
+	case info.S > MATE - MAX_PLY:
+		return (MATE - info.S + 1) / 2, true
+	case info.S < -MATE + MAX_PLY:
+		return -(MATE + info.S) / 2, true
+	}
+	return 0, false
+}
+
+// A Searcher searches positions for their best move. One Searcher runs one search at a time; Stop may be
+// called from another goroutine to end it early. What it learns (the transposition table and the history of
+// good quiet moves) carries over from one search to the next, which helps when they are moves of one game.
+type Searcher struct{
+	OnInfo func(Info)      // called after each completed depth, if set
+	TT *TranspositionTable // positions already searched                          ([T]ransposition [T]able)
+
+	stopped int32                     // set by Stop, cleared by Reset
+	limited bool                      // the search has run into its node or time limit
+	nodes int
+	maxNodes int
+	nextCheck int          // node count at which to next look at the clock
+	deadline time.Time
+	start time.Time
+	pv [MAX_PLY + 1]board.MoveSequence
+	buffers [MAX_PLY + 1]board.MoveSequence
+	scores [MAX_PLY + 1][]int         // ordering scores for buffers
+	killers [MAX_PLY + 1][2]uint16    // quiet moves that last caused a cutoff at each ply
+	history [2][64][64]int            // how often quiet moves have caused cutoffs, by player, from and to
+	keys []uint64                     // keys of the positions in the game up to the root, then the search path
+	root int                          // index of the root position in keys
+}
+
+// NewSearcher returns a Searcher ready to search, with a transposition table of the default size.
+func NewSearcher() *Searcher {
+	return &Searcher{TT: NewTranspositionTable(DEFAULT_HASH_MB)}
+}
+
+// BestMove searches g within limits and returns the best move it finds, or false if there are no legal moves.
+func BestMove(g *board.GameState, limits Limits) (board.Move, bool) {
+	m, _, ok := NewSearcher().Search(g, limits)
+	return m, ok
+}
+
+// Stop ends the search in progress, which returns the best move from the last depth it completed. If no
+// search is running yet, the next one stops as soon as it starts; Reset undoes that.
+func (s *Searcher) Stop() {
+	atomic.StoreInt32(&s.stopped, 1)
+}
+
+// Reset readies the Searcher for a new search after Stop. Call it before starting a search that may be
+// stopped from another goroutine, rather than in that goroutine, so a Stop sent straight after isn't lost.
+func (s *Searcher) Reset() {
+	atomic.StoreInt32(&s.stopped, 0)
+}
+
+// Clear forgets everything learned from earlier searches, as before a new game.
+func (s *Searcher) Clear() {
+	s.TT.Clear()
+	s.killers = [MAX_PLY + 1][2]uint16{}
+	s.history = [2][64][64]int{}
+}
+
+// Search returns the best move it finds for the player to move in g, or false if there are no legal moves,
+// along with the last completed depth's Info. g is left as it was.
+func (s *Searcher) Search(g *board.GameState, limits Limits) (board.Move, Info, bool) {
+	s.limited = false
+	s.nodes, s.maxNodes, s.nextCheck = 0, limits.N, 0
+	s.start = time.Now()
+	s.deadline = time.Time{}
+	if budget := timeBudget(g, limits); budget > 0 {
+		s.deadline = s.start.Add(budget)
+	}
+	s.startKeys(g)
+	s.killers = [MAX_PLY + 1][2]uint16{}
+	s.ageHistory(8)
+
+	moves := board.LegalMoves(g)
+	if len(moves) == 0 {
+		return board.Move{}, Info{}, false
+	}
+	best := Info{PV: board.MoveSequence{moves[0]}}
+	maxDepth := limits.D
+	if (maxDepth <= 0) || (maxDepth > MAX_PLY) {
+		maxDepth = MAX_PLY
+	}
+	for depth := 1; depth <= maxDepth; depth++ {
+		score := s.negamax(g, depth, 0, -INFINITY, INFINITY)
+		if s.shouldStop() {
+			break
+		}
+		best = Info{D: depth, S: score, N: s.nodes, T: time.Since(s.start), PV: s.pv[0].Copy()}
+		if s.OnInfo != nil {
+			s.OnInfo(best)
+		}
+		if (len(moves) == 1) && (limits.D == 0) {
+			// nothing to think about
+			break
+		}
+		if _, mate := best.MateIn(); mate && (limits.D == 0) {
+			break
+		}
+	}
+	return best.PV[0], best, true
+}
+
+func (s *Searcher) startKeys(g *board.GameState) {
+	// The keys of the positions since the last capture or pawn move, which the search may repeat.
+	s.keys = s.keys[:0]
+	first := len(g.H) - 1 - g.HC
+	if first < 0 {
+		first = 0
+		if start, err := board.LoadFEN(g.SP); (err == nil) && (len(g.H) > 0) {
+			s.keys = append(s.keys, start.Key())
+		}
+	}
+	for _, e := range g.H[first:] {
+		s.keys = append(s.keys, e.K)
+	}
+	if (len(s.keys) == 0) || (s.keys[len(s.keys) - 1] != g.Key()) {
+		s.keys = append(s.keys, g.Key())
+	}
+	s.root = len(s.keys) - 1
+}
+
+func (s *Searcher) shouldStop() bool {
+	if s.limited || (atomic.LoadInt32(&s.stopped) != 0) {
+		return true
+	}
+	if s.nodes >= s.nextCheck {
+		s.nextCheck = s.nodes + 1024
+		if ((s.maxNodes > 0) && (s.nodes >= s.maxNodes)) || (!s.deadline.IsZero() && time.Now().After(s.deadline)) {
+			s.limited = true
+		}
+	}
+	return s.limited
+}
+
+func (s *Searcher) isRepetition(g *board.GameState, ply int) bool {
+	// Whether the position at ply has come up before. A position repeated even once is scored as a draw:
+	// if it was worth repeating once, the same moves can repeat it again.
+	i := s.root + ply
+	for j := i - 2; (j >= 0) && (j >= i - g.HC); j -= 2 {
+		if s.keys[j] == s.keys[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Searcher) negamax(g *board.GameState, depth int, ply int, alpha int, beta int) int {
+	// The score of g for the player to move, exact if it lies between alpha and beta; otherwise only the
+	// side of the window it falls on is right. Fills s.pv[ply] with the line leading to it.
+	s.pv[ply] = s.pv[ply][:0]
+	key := g.Key()
+	s.keys = append(s.keys[:s.root + ply], key)
+	if (ply > 0) && (s.isRepetition(g, ply) || board.InsufficientMaterial(g)) {
+		return 0
+	}
+	inCheck := board.CheckForCheck(g, g.PL)
+	if inCheck {
+		// look a ply further at checks, so that mates and escapes aren't cut off at the horizon
+		depth += 1
+	}
+	if (depth <= 0) || (ply == MAX_PLY) {
+		return s.quiesce(g, ply, alpha, beta)
+	}
+	s.nodes += 1
+
+	ttMove := uint16(0)
+	if e, found := s.TT.probe(key); found {
+		ttMove = e.move
+		score := scoreFromTT(int(e.score), ply)
+		// not in the principal variation, where cutting off would lose the rest of the line
+		if (beta - alpha == 1) && (int(e.depth) >= depth) && ((e.bound == EXACT) ||
+			((e.bound == LOWER_BOUND) && (score >= beta)) || ((e.bound == UPPER_BOUND) && (score <= alpha))) {
+			return score
+		}
+	}
+
+	moves := board.AppendMoves(g, s.buffers[ply][:0], board.ALL_MOVES)
+	s.buffers[ply] = moves
+	if len(moves) == 0 {
+		if inCheck {
+			return -MATE + ply
+		}
+		return 0
+	}
+	if (ply > 0) && (g.HC >= 100) {
+		return 0
+	}
+	s.orderMoves(g, ply, ttMove)
+
+	startAlpha := alpha
+	bestMove := uint16(0)
+	for i := range moves {
+		m := s.pickMove(ply, i)
+		e := board.PlayMove(g, m)
+		score := 0
+		if i == 0 {
+			score = -s.negamax(g, depth - 1, ply + 1, -beta, -alpha)
+		} else {
+			// With good ordering the first move is usually best, so just check that the others aren't
+			// better, which an empty window does quickest, and only search them properly if they are.
+			score = -s.negamax(g, depth - 1, ply + 1, -alpha - 1, -alpha)
+			if (score > alpha) && (score < beta) {
+				score = -s.negamax(g, depth - 1, ply + 1, -beta, -alpha)
+			}
+		}
+		board.TakeBack(g, e)
+		if s.shouldStop() {
+			return 0
+		}
+		if score > alpha {
+			alpha = score
+			bestMove = packMove(m)
+			s.pv[ply] = append(append(s.pv[ply][:0], m), s.pv[ply + 1]...)
+			if alpha >= beta {
+				if (e.CP == board.EMPTY_SQUARE) && (m.P == e.MP) {
+					s.rememberQuietMove(g.PL, m, depth, ply)
+				}
+				break
+			}
+		}
+	}
+
+	bound := EXACT
+	if alpha >= beta {
+		bound = LOWER_BOUND
+	} else if alpha == startAlpha {
+		bound = UPPER_BOUND
+	}
+	s.TT.store(key, depth, bound, scoreToTT(alpha, ply), bestMove)
+	return alpha
+}
+
+func (s *Searcher) quiesce(g *board.GameState, ply int, alpha int, beta int) int {
+	// Settles the position before evaluating it by playing out the captures, so that a search stopping
+	// halfway through an exchange doesn't count the material as it stands. Not capturing is an option too,
+	// so the static evaluation is a lower bound, except in check where every escape has to be looked at.
+	s.nodes += 1
+	s.pv[ply] = s.pv[ply][:0]
+	if ply == MAX_PLY {
+		return Evaluate(g)
+	}
+	filter := board.CAPTURES
+	inCheck := board.CheckForCheck(g, g.PL)
+	if inCheck {
+		filter = board.ALL_MOVES
+	} else {
+		standPat := Evaluate(g)
+		if standPat >= beta {
+			return standPat
+		}
+		if standPat > alpha {
+			alpha = standPat
+		}
+	}
+
+	moves := board.AppendMoves(g, s.buffers[ply][:0], filter)
+	s.buffers[ply] = moves
+	if inCheck && (len(moves) == 0) {
+		return -MATE + ply
+	}
+	s.orderMoves(g, ply, 0)
+	for i := range moves {
+		m := s.pickMove(ply, i)
+		e := board.PlayMove(g, m)
+		score := -s.quiesce(g, ply + 1, -beta, -alpha)
+		board.TakeBack(g, e)
+		if s.shouldStop() {
+			return 0
+		}
+		if score > alpha {
+			alpha = score
+			s.pv[ply] = append(append(s.pv[ply][:0], m), s.pv[ply + 1]...)
+			if alpha >= beta {
+				break
+			}
+		}
+	}
+	return alpha
+}
+
+func timeBudget(g *board.GameState, limits Limits) time.Duration {
+	// How long to think about this move: the move time if there is one, otherwise a share of the clock.
+	if limits.MT > 0 {
+		return limits.MT
+	}
+	left, increment := limits.T[g.PL], limits.I[g.PL]
+	if left <= 0 {
+		return 0
+	}
+	movesToGo := limits.MTG
+	if (movesToGo <= 0) || (movesToGo > 30) {
+		movesToGo = 30
+	}
+	budget := left / time.Duration(movesToGo) + increment * 3 / 4
+	if budget > left / 2 {
+		budget = left / 2
+	}
+	return budget
+}
@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"chess/board"
+)
+
+func mustLoadFEN(t *testing.T, fen string) *board.GameState {
+	t.Helper()
+	g, err := board.LoadFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestMates(t *testing.T) {
+	for _, test := range []struct{
+		fen string
+		move string
+		mateIn int
+	}{
+		{"r1bqkb1r/pppp1ppp/2n2n2/4p2Q/2B1P3/8/PPPP1PPP/RNB1K1NR w KQkq - 4 4", "h5f7", 1},
+		{"kbK5/pp6/1P6/8/8/8/8/R7 w - - 0 1", "a1a6", 2},
+		{"6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", "a1a8", 1},
+	} {
+		g := mustLoadFEN(t, test.fen)
+		m, info, ok := NewSearcher().Search(g, Limits{D: 5})
+		if !ok {
+			t.Errorf("%s: no move", test.fen)
+			continue
+		}
+		if got := board.CoordinateNotation(g, m); got != test.move {
+			t.Errorf("%s: played %s, want %s", test.fen, got, test.move)
+		}
+		if n, mate := info.MateIn(); !mate || (n != test.mateIn) {
+			t.Errorf("%s: score %d, want mate in %d", test.fen, info.S, test.mateIn)
+		}
+		if g.FEN() != test.fen {
+			t.Errorf("search left the position as %s", g.FEN())
+		}
+	}
+}
+
+func TestQuiescence(t *testing.T) {
+	// at depth 1 the queen can take a pawn, but the capture back only shows up in the quiescence search
+	g := mustLoadFEN(t, "4k3/2p5/3p4/8/8/8/3Q4/4K3 w - - 0 1")
+	m, ok := BestMove(g, Limits{D: 1})
+	if !ok || (board.CoordinateNotation(g, m) == "d2d6") {
+		t.Errorf("took the defended pawn")
+	}
+
+	g = mustLoadFEN(t, "4k3/8/8/3q4/4P3/8/8/4K3 w - - 0 1")
+	if m, _ := BestMove(g, Limits{D: 1}); board.CoordinateNotation(g, m) != "e4d5" {
+		t.Errorf("left the queen hanging")
+	}
+}
+
+func TestRepetitionIsADraw(t *testing.T) {
+	// black is a queen down, so would rather repeat the position than play on
+	g := mustLoadFEN(t, "6k1/8/8/8/8/8/8/1Q4K1 b - - 0 1")
+	for _, text := range []string{"Kh8", "Kh1", "Kg8", "Kg1"} {
+		m, err := board.ParseMove(g, text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		board.MakeMove(g, m)
+	}
+	s := NewSearcher()
+	s.startKeys(g)
+	if !s.isRepetition(g, 0) {
+		t.Errorf("the root repeats the starting position")
+	}
+	m, _ := board.ParseMove(g, "Kh8")
+	e := board.PlayMove(g, m)
+	s.keys = append(s.keys[:s.root + 1], g.Key())
+	if !s.isRepetition(g, 1) {
+		t.Errorf("Kh8 repeats the position")
+	}
+	board.TakeBack(g, e)
+
+	if _, info, _ := s.Search(g, Limits{D: 4}); info.S != 0 {
+		t.Errorf("scored %d, want a draw", info.S)
+	}
+}
+
+func TestRepeatingThePawnMove(t *testing.T) {
+	// white, a queen down, can go back to the position just after a3, the last pawn move
+	g := mustLoadFEN(t, "1q4k1/8/8/8/8/8/P7/7K w - - 0 1")
+	for _, text := range []string{"a3", "Kg7", "Kg1", "Kg8"} {
+		m, err := board.ParseMove(g, text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		board.MakeMove(g, m)
+	}
+	if _, info, _ := NewSearcher().Search(g, Limits{D: 4}); info.S != 0 {
+		t.Errorf("scored %d, want a draw", info.S)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	g, _ := board.NewGame()
+	s := NewSearcher()
+	depths := 0
+	s.OnInfo = func(info Info) {
+		depths += 1
+		if info.D != depths || len(info.PV) == 0 {
+			t.Errorf("info %+v after %d depths", info, depths - 1)
+		}
+	}
+	if _, info, _ := s.Search(g, Limits{D: 4}); (info.D != 4) || (depths != 4) {
+		t.Errorf("searched to depth %d, reported %d", info.D, depths)
+	}
+
+	s.OnInfo = nil
+	start := time.Now()
+	if _, _, ok := s.Search(g, Limits{MT: 100 * time.Millisecond}); !ok {
+		t.Errorf("no move within the move time")
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("100ms search took %v", time.Since(start))
+	}
+
+	if _, info, _ := s.Search(g, Limits{N: 5000}); info.N > 5000 + 1024 {
+		t.Errorf("5000-node search went to %d nodes", info.N)
+	}
+
+	g = mustLoadFEN(t, "7k/6Q1/5K2/8/8/8/8/8 b - - 0 1")
+	if _, ok := BestMove(g, Limits{D: 3}); ok {
+		t.Errorf("found a move when mated")
+	}
+}
+
+func TestTranspositionTable(t *testing.T) {
+	tt := NewTranspositionTable(1)
+	if len(tt.entries) != 1 << 16 {
+		t.Errorf("1MB table has %d entries", len(tt.entries))
+	}
+	tt.store(12345, 3, LOWER_BOUND, scoreToTT(MATE - 5, 2), 77)
+	e, found := tt.probe(12345)
+	if !found || (e.move != 77) || (scoreFromTT(int(e.score), 4) != MATE - 7) {
+		t.Errorf("probe gave %+v, %v", e, found)
+	}
+	if _, found := tt.probe(12345 + 1 << 16); found {
+		t.Errorf("found a different position in the same slot")
+	}
+	tt.store(54321, MAX_PLY + 10, EXACT, 0, 0)
+	if e, found := tt.probe(54321); !found || (int(e.depth) != MAX_PLY + 10) {
+		t.Errorf("depth %d came back as %d", MAX_PLY + 10, e.depth)
+	}
+	tt.Clear()
+	if _, found := tt.probe(12345); found {
+		t.Errorf("found a position after clearing")
+	}
+}
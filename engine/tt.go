@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"chess/board"
+)
+
+// Bounds a stored score can be.
+const (
+	EXACT uint8 = iota
+	LOWER_BOUND    // the search failed high: the score is at least this
+	UPPER_BOUND    // the search failed low: the score is at most this
+)
+
+const DEFAULT_HASH_MB = 16
+
+type ttEntry struct{
+	key uint64
+	move uint16     // see packMove
+	score int16
+	depth int16     // more than int8 holds: deepening goes to MAX_PLY, and check extensions past it
+	bound uint8
+}
+
+// A TranspositionTable remembers the results of searching positions, keyed by their Zobrist key, so that
+// positions reached again by another move order, or again at the next depth, needn't be searched again.
+type TranspositionTable struct{
+	entries []ttEntry
+	mask uint64
+}
+
+// NewTranspositionTable returns a table taking up at most mb megabytes.
+func NewTranspositionTable(mb int) *TranspositionTable {
+	size := uint64(1)
+	for size * 2 * 16 <= uint64(mb) << 20 {
+		size *= 2
+	}
+	return &TranspositionTable{entries: make([]ttEntry, size), mask: size - 1}
+}
+
+// Clear forgets everything, as before a new game.
+func (tt *TranspositionTable) Clear() {
+	for i := range tt.entries {
+		tt.entries[i] = ttEntry{}
+	}
+}
+
+func (tt *TranspositionTable) probe(key uint64) (ttEntry, bool) {
+	e := tt.entries[key & tt.mask]
+	return e, (e.key == key) && (e.depth > 0 || e.move != 0)
+}
+
+func (tt *TranspositionTable) store(key uint64, depth int, bound uint8, score int, move uint16) {
+	e := &tt.entries[key & tt.mask]
+	// keep the deeper result for the same position, but always make room for a new one
+	if (e.key == key) && (int(e.depth) > depth) && (bound != EXACT) {
+		return
+	}
+	if (move == 0) && (e.key == key) {
+		move = e.move
+	}
+	*e = ttEntry{key: key, move: move, score: int16(score), depth: int16(depth), bound: bound}
+}
+
+func packMove(m board.Move) uint16 {
+	// 6 bits each for the two squares and 3 for the resulting piece type, enough to tell the moves of one
+	// position apart
+	from, to := board.Square(m.SF, m.SR), board.Square(m.DF, m.DR)
+	return uint16(from) | uint16(to) << 6 | uint16(m.P & board.TYPE_MASK) << 12
+}
+
+func scoreToTT(score int, ply int) int {
+	// Mate scores count plies from the root; stored, they count from the position itself.
+	if score > MATE - MAX_PLY {
+		return score + ply
+	} else if score < -MATE + MAX_PLY {
+		return score - ply
+	}
+	return score
+}
+
+func scoreFromTT(score int, ply int) int {
+	if score > MATE - MAX_PLY {
+		return score - ply
+	} else if score < -MATE + MAX_PLY {
+		return score + ply
+	}
+	return score
+}
@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+
+	"chess/board"
+)
+
+// A LabelledPosition is a position from a game along with how the game ended, for tuning.
+type LabelledPosition struct{
+	G *board.GameState     // the position                                    ([G]ame)
+	R float64              // the result for white: 1 won, 0.5 drawn, 0 lost  ([R]esult)
+}
+
+// Results as they appear in labelled position files: PGN style, as in EPD c9 "1-0"; or as a score for
+// white, as in FEN [0.5].
+var LABEL_RESULTS = map[string]float64{
+	"1-0": 1, "0-1": 0, "1/2-1/2": 0.5,
+	"1.0": 1, "0.0": 0, "0.5": 0.5,
+}
+
+// ParseLabelledPosition reads one line of an EPD or FEN file that gives the game's result somewhere after the
+// position, such as `rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - c9 "1/2-1/2";` or
+// `rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1 [0.5]`.
+func ParseLabelledPosition(line string) (LabelledPosition, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 {
+		return LabelledPosition{}, fmt.Errorf("Expected a position and a result in %q.", line)
+	}
+	result, found := 0.0, false
+	for _, field := range fields[4:] {
+		if r, ok := LABEL_RESULTS[strings.Trim(field, "\"[];")]; ok {
+			result, found = r, true
+		}
+	}
+	if !found {
+		return LabelledPosition{}, fmt.Errorf("No result in %q.", line)
+	}
+	// EPD leaves out the move counters, which don't matter to the evaluation anyway
+	g, err := board.LoadFEN(strings.Join(fields[:4], " ") + " 0 1")
+	if err != nil {
+		return LabelledPosition{}, err
+	}
+	return LabelledPosition{G: g, R: result}, nil
+}
+
+// ReadLabelledPositions reads a file of labelled positions, one per line. Blank lines and lines starting
+// with # are skipped.
+func ReadLabelledPositions(r io.Reader) ([]LabelledPosition, error) {
+	positions := []LabelledPosition{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line += 1
+		text := strings.TrimSpace(scanner.Text())
+		if (text == "") || strings.HasPrefix(text, "#") {
+			continue
+		}
+		position, err := ParseLabelledPosition(text)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %v", line, err)
+		}
+		positions = append(positions, position)
+	}
+	return positions, scanner.Err()
+}
+
+// A Tuner fits the evaluation weights to the results of games, Texel style: the evaluation of each position,
+// squashed into an expected result by a logistic curve, should predict how its game ended. The positions
+// ought to be quiet, since they are evaluated as they stand, with no search.
+type Tuner struct{
+	P []LabelledPosition   // the positions to fit to                                  ([P]ositions)
+	K float64              // steepness of the curve from centipawns to expected result
+	W int                  // goroutines to share the positions between                ([W]orkers)
+	OnPass func(pass int, p *Params, e float64)   // called after each pass of Tune, if set
+}
+
+func sigmoid(k float64, score int) float64 {
+	// The expected result for a score, from 0 to 1: 400 * K centipawns up is 10:1 odds.
+	return 1 / (1 + math.Pow(10, -k * float64(score) / 400))
+}
+
+// Error is the mean squared difference between the results and what p's evaluations expect them to be.
+func (t *Tuner) Error(p *Params) float64 {
+	return t.errorWith(p, t.K)
+}
+
+func (t *Tuner) errorWith(p *Params, k float64) float64 {
+	if len(t.P) == 0 {
+		return 0
+	}
+	workers := t.W
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(t.P) {
+		// or some would be left with nothing to do, and start past the end
+		workers = len(t.P)
+	}
+	sums := make([]float64, workers)
+	var wg sync.WaitGroup
+	chunk := (len(t.P) + workers - 1) / workers
+	for w := 0; w < workers; w++ {
+		start, end := w * chunk, (w + 1) * chunk
+		if end > len(t.P) {
+			end = len(t.P)
+		}
+		wg.Add(1)
+		go func(w int, positions []LabelledPosition) {
+			defer wg.Done()
+			sum := 0.0
+			for _, lp := range positions {
+				score := p.Evaluate(lp.G)
+				if lp.G.PL == 1 {
+					score = -score
+				}
+				diff := lp.R - sigmoid(k, score)
+				sum += diff * diff
+			}
+			sums[w] = sum
+		}(w, t.P[start:end])
+	}
+	wg.Wait()
+	total := 0.0
+	for _, sum := range sums {
+		total += sum
+	}
+	return total / float64(len(t.P))
+}
+
+// FitK sets K to the steepness that best fits p's evaluations to the results, so that tuning changes the
+// weights rather than just their scale, and returns it.
+func (t *Tuner) FitK(p *Params) float64 {
+	// the error is smooth with one minimum in K, so narrow in on it by golden section search
+	low, high := 0.0, 10.0
+	ratio := (math.Sqrt(5) - 1) / 2
+	for high - low > 0.0001 {
+		a, b := high - ratio * (high - low), low + ratio * (high - low)
+		if t.errorWith(p, a) < t.errorWith(p, b) {
+			high = b
+		} else {
+			low = a
+		}
+	}
+	t.K = (low + high) / 2
+	return t.K
+}
+
+// Tune improves on p by local search: each pass nudges every weight up or down a step, keeping changes
+// that lower the error, until a pass changes nothing or passes run out (0 means no limit). p is left as it
+// was and the tuned weights are returned.
+func (t *Tuner) Tune(p *Params, step int, passes int) *Params {
+	tuned := *p
+	values := tuned.Values()
+	best := t.Error(&tuned)
+	for pass := 1; (passes <= 0) || (pass <= passes); pass++ {
+		improved := false
+		for i := range values {
+			for _, delta := range []int{step, -step} {
+				values[i] += delta
+				tuned.SetValues(values)
+				if e := t.Error(&tuned); e < best {
+					best = e
+					improved = true
+					break
+				}
+				values[i] -= delta
+				tuned.SetValues(values)
+			}
+		}
+		if t.OnPass != nil {
+			t.OnPass(pass, &tuned, best)
+		}
+		if !improved {
+			break
+		}
+	}
+	return &tuned
+}
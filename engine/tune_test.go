@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseLabelledPosition(t *testing.T) {
+	for line, want := range map[string]float64{
+		`rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - c9 "1/2-1/2";`: 0.5,
+		`rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1 [1.0]`: 1,
+		`rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1 0-1`: 0,
+	} {
+		lp, err := ParseLabelledPosition(line)
+		if err != nil {
+			t.Errorf("%s: %v", line, err)
+			continue
+		}
+		if (lp.R != want) || (lp.G.PL != 1) {
+			t.Errorf("%s: result %v, player %d", line, lp.R, lp.G.PL)
+		}
+	}
+
+	_, err := ReadLabelledPositions(strings.NewReader("# header\n\n8/8/8/8/8/8/8/8 w - - 0 1\n"))
+	if (err == nil) || !strings.HasPrefix(err.Error(), "Line 3: No result") {
+		t.Errorf("unlabelled position gave %v", err)
+	}
+}
+
+func TestTune(t *testing.T) {
+	// White keeps winning a knight up, so the knight should be worth more afterwards.
+	positions, err := ReadLabelledPositions(strings.NewReader(`
+4k3/pppp4/8/8/8/8/PPPP4/1N2K3 w - - c9 "1-0";
+4k3/pppp4/8/8/8/8/PPPP4/4KN2 b - - c9 "1-0";
+1n2k3/pppp4/8/8/8/8/PPPP4/4K3 w - - c9 "0-1";
+4k3/pppp4/8/8/8/8/PPPP4/4K3 w - - c9 "1/2-1/2";
+4k3/pppp4/8/8/8/8/PPPP4/3NK3 b - - c9 "1-0";
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tuner := &Tuner{P: positions, W: 3}
+	if k := tuner.FitK(&DEFAULT_PARAMS); (k <= 0) || (k >= 10) {
+		t.Errorf("fitted K to %v", k)
+	}
+	before := tuner.Error(&DEFAULT_PARAMS)
+	passes := 0
+	tuner.OnPass = func(pass int, p *Params, e float64) {
+		passes = pass
+	}
+	tuned := tuner.Tune(&DEFAULT_PARAMS, 5, 2)
+	if after := tuner.Error(tuned); after >= before {
+		t.Errorf("error went from %v to %v", before, after)
+	}
+	if passes != 2 {
+		t.Errorf("ran %d passes", passes)
+	}
+	if tuned.MV[EG][2] <= DEFAULT_PARAMS.MV[EG][2] {
+		t.Errorf("knight went from %d to %d", DEFAULT_PARAMS.MV[EG][2], tuned.MV[EG][2])
+	}
+	if DEFAULT_PARAMS.MV[EG][2] != 300 {
+		t.Errorf("tuning changed the defaults")
+	}
+
+	// more workers than positions
+	many := &Tuner{P: positions, K: tuner.K, W: 8}
+	if e := many.Error(&DEFAULT_PARAMS); math.Abs(e - before) > 1e-9 {
+		t.Errorf("8 workers gave error %v, 3 gave %v", e, before)
+	}
+}
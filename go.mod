@@ -2,4 +2,4 @@ module chess
 
 go 1.16
 
-require github.com/veandco/go-sdl2 v0.4.10 // indirect
+require github.com/veandco/go-sdl2 v0.4.10
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// The size of a board square when the window opens. Everything else in the window is measured in squares:
+// a margin a third of a square wide for the coordinates down the left and along the bottom, the board, a
+// quarter-square gap, and the side panel, PANEL_SQUARES wide.
+const SQUARE_WIDTH = 80
+const PANEL_SQUARES = 4
+const MIN_SQUARE_WIDTH = 24
+
+// A Layout is where everything goes in a window of a given size.
+type Layout struct{
+	S int32        // side of a board square         ([S]quare)
+	B sdl.Rect     // the board                      ([B]oard)
+	P sdl.Rect     // the side panel                 ([P]anel)
+	W int32        // window width                   ([W]idth)
+	H int32        // window height                  ([H]eight)
+}
+
+func windowSize(square int32) (int32, int32) {
+	// How big a window squares of this size need.
+	return square / 3 + 8 * square + square / 4 + PANEL_SQUARES * square, 8 * square + square / 3
+}
+
+// NewLayout fits the biggest board it can into a window of w by h, with anything left over spread evenly
+// around the edges.
+func NewLayout(w int32, h int32) Layout {
+	square := int32(MIN_SQUARE_WIDTH)
+	for {
+		needW, needH := windowSize(square + 1)
+		if (needW > w) || (needH > h) {
+			break
+		}
+		square += 1
+	}
+	needW, needH := windowSize(square)
+	left, top := (w - needW) / 2, (h - needH) / 2
+	if left < 0 {
+		left = 0
+	}
+	if top < 0 {
+		top = 0
+	}
+	l := Layout{S: square, W: w, H: h}
+	l.B = sdl.Rect{X: left + square / 3, Y: top, W: 8 * square, H: 8 * square}
+	l.P = sdl.Rect{X: l.B.X + l.B.W + square / 4, Y: top, W: PANEL_SQUARES * square, H: 8 * square}
+	return l
+}
+
+// SquareRect is where a square is drawn: with white at the bottom unless flipped, when black is.
+func (l Layout) SquareRect(file int, rank int, flipped bool) sdl.Rect {
+	col, row := file - 'A', 8 - rank
+	if flipped {
+		col, row = 'H' - file, rank - 1
+	}
+	return sdl.Rect{X: l.B.X + l.S * int32(col), Y: l.B.Y + l.S * int32(row), W: l.S, H: l.S}
+}
+
+// SquareAt is the file and rank of the square drawn at x, y, the inverse of SquareRect, or false if x, y
+// is off the board.
+func (l Layout) SquareAt(x int32, y int32, flipped bool) (int, int, bool) {
+	if !inRect(l.B, x, y) {
+		return 0, 0, false
+	}
+	col, row := int((x - l.B.X) / l.S), int((y - l.B.Y) / l.S)
+	if flipped {
+		return 'H' - col, row + 1, true
+	}
+	return 'A' + col, 8 - row, true
+}
+
+// PickerRect is where the promotion picker shows its i'th piece: in a row across the middle of the board.
+func (l Layout) PickerRect(i int) sdl.Rect {
+	left := l.B.X + (l.B.W - int32(len(PICKER_PIECES)) * l.S) / 2
+	return sdl.Rect{X: left + l.S * int32(i), Y: l.B.Y + (l.B.H - l.S) / 2, W: l.S, H: l.S}
+}
+
+func inRect(rect sdl.Rect, x int32, y int32) bool {
+	return (x >= rect.X) && (x < rect.X + rect.W) && (y >= rect.Y) && (y < rect.Y + rect.H)
+}
@@ -2,86 +2,170 @@ package main
 
 import (
 	"os"
+	"flag"
 	"fmt"
+	"time"
+	"chess/board"
+	"chess/engine"
 	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
 )
 
-func run() error {
+// How long the window waits for an event before looking again for the computer's move, in milliseconds.
+const IDLE_WAIT = 250
+const THINKING_WAIT = 20
+
+func run(fen string, pgnPath string, white string, black string, computer [2]bool, limits engine.Limits, autoQueen bool, fontPath string) error {
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		fmt.Println("Error initializing SDL:", err)
 		return err
 	}
 	defer sdl.Quit()
+	if err := ttf.Init(); err != nil {
+		fmt.Println("Error initializing SDL_ttf:", err)
+		return err
+	}
+	defer ttf.Quit()
 
+	layout := NewLayout(windowSize(SQUARE_WIDTH))
 	window, err := sdl.CreateWindow(
 		"Chess",
 		sdl.WINDOWPOS_UNDEFINED,
 		sdl.WINDOWPOS_UNDEFINED,
-		screenWidth,
-		screenHeight,
-		sdl.WINDOW_OPENGL)
+		layout.W,
+		layout.H,
+		sdl.WINDOW_OPENGL | sdl.WINDOW_RESIZABLE)
 	if err != nil {
 		fmt.Println("Error creating window:", err)
 		return err
 	}
 	defer window.Destroy()
 
-	renderer, err := sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED)
+	renderer, err := sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED | sdl.RENDERER_PRESENTVSYNC)
 	if err != nil {
 		fmt.Println("Error initializing renderer:", err)
 		return err
 	}
 	defer renderer.Destroy()
 
-	b, err := initializeBoard()
+	screen, err := NewScreen(renderer, layout)
 	if err != nil {
-		fmt.Println("Board is broken:", err)
+		fmt.Println("Error loading the piece images:", err)
 		return err
 	}
+	defer screen.Destroy()
 
-	h := make([]Move, 0)
+	panel := &Panel{FP: fontPath}
+	panel.SetFontSize(int(layout.S / 4))
+	defer panel.Close()
+
+	g, err := board.LoadFEN(fen)
+	if err != nil {
+		fmt.Println("Board is broken:", err)
+		return err
+	}
+	started := time.Now()
+	if pgnPath == "" {
+		pgnPath = started.Format("chess-2006-01-02-150405.pgn")
+	}
+	save := func(result board.GameResult) {
+		if err := board.SavePGN(pgnPath, board.NewPGNGame(g, board.PGNResult(g, result), white, black, started)); err != nil {
+			fmt.Println("Could not save the game:", err)
+		} else {
+			fmt.Println("Game saved to", pgnPath)
+		}
+	}
 
 	var selectedPiece []int = nil
 	var tempPiece []int = nil
-	var legalMoves MoveSequence = nil
+	var legalMoves board.MoveSequence = nil
+	var promotions board.MoveSequence = nil    // the moves on offer while the promotion picker is open
+
+	// White at the bottom, unless the human plays black against the computer; F turns the board around.
+	flipped := computer[0] && !computer[1]
 
 	mousePressed := false
 	moveMade := false
 
-	player := 0
-	isPlayer := isWhite
-	isOpponent := isBlack
-	ended := false
+	result := board.IN_PROGRESS
+	ended := false    // the end of the game has been announced, and the game saved
+
+	// The computer thinks in the background so the window keeps responding; its move arrives on thinking.
+	searcher := engine.NewSearcher()
+	var thinking chan board.Move = nil
 
+	// Nothing moves on the screen unless something happens, so wait for events rather than spinning, and only
+	// draw again when one of them changes something. While the computer thinks, look for its move every
+	// THINKING_WAIT milliseconds.
+	redraw := true
 	for {
-		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
+		wait := IDLE_WAIT
+		if thinking != nil {
+			wait = THINKING_WAIT
+		}
+		for event := sdl.WaitEventTimeout(wait); event != nil; event = sdl.PollEvent() {
 			switch t := event.(type) {
 			case *sdl.QuitEvent:
+				searcher.Stop()
 				return nil
+			case *sdl.WindowEvent:
+				// resized, uncovered or the like
+				if t.Event == sdl.WINDOWEVENT_SIZE_CHANGED {
+					screen.L = NewLayout(t.Data1, t.Data2)
+					panel.SetFontSize(int(screen.L.S / 4))
+				}
+				redraw = true
+			case *sdl.MouseWheelEvent:
+				panel.Scroll(int(t.Y))
+				redraw = true
 			case *sdl.MouseButtonEvent:
-				if t.State == sdl.PRESSED && !mousePressed {
-					tempPiece = []int{int(t.X / SQUARE_WIDTH) + 65, int(t.Y / SQUARE_WIDTH) + 1}
+				redraw = true
+				if t.State == sdl.PRESSED && !mousePressed && promotions != nil {
+					// clicking a piece in the picker promotes to it; clicking anywhere else closes it
+					if move, ok := promotionAt(promotions, screen.L, t.X, t.Y); ok {
+						board.MakeMove(g, move)
+						result = board.Result(g)
+					}
+					promotions = nil
+					mousePressed = true
+				}
+				if t.State == sdl.PRESSED && !mousePressed && !computer[g.PL] && (result == board.IN_PROGRESS) {
+					file, rank, onBoard := screen.L.SquareAt(t.X, t.Y, flipped)
+					if !onBoard {
+						break
+					}
+					tempPiece = []int{file, rank}
 					if selectedPiece != nil {
+						// a pawn reaching the last rank has a move for each piece it can become
+						choices := board.MoveSequence{}
 						for _, move := range legalMoves {
 							if (tempPiece[0] == move.DF) && (tempPiece[1] == move.DR) {
-								makeMove(b, h, move)
-								moveMade = true
-								player = 1 - player
-								isPlayer, isOpponent = isOpponent, isPlayer
-								ended = gameOver(b, h, player)
-								break
+								choices = append(choices, move)
 							}
 						}
+						if len(choices) == 1 {
+							board.MakeMove(g, choices[0])
+							result = board.Result(g)
+							moveMade = true
+						} else if len(choices) > 1 && autoQueen {
+							move, _ := promotionTo(choices, board.QUEEN)
+							board.MakeMove(g, move)
+							result = board.Result(g)
+							moveMade = true
+						} else if len(choices) > 1 {
+							promotions = choices
+							moveMade = true
+						}
 						selectedPiece = nil
 						legalMoves = nil
 					}
 					if !moveMade {
-						if b[tempPiece[0]][tempPiece[1]] == EMPTY_SQUARE || isOpponent(b[tempPiece[0]][tempPiece[1]]){
+						if board.PlayerOf(g.B.At(tempPiece[0], tempPiece[1])) != g.PL {
 							selectedPiece = nil
 							legalMoves = nil
 						} else {
 							selectedPiece = tempPiece
-							legalMoves = generateLegalMoves(b, h, selectedPiece[0], selectedPiece[1], player, false)
+							legalMoves = board.GenerateLegalMoves(g, selectedPiece[0], selectedPiece[1])
 						}
 						mousePressed = true
 					}
@@ -90,17 +174,100 @@ func run() error {
 				if t.State == sdl.RELEASED {
 					mousePressed = false
 				}
+			case *sdl.KeyboardEvent:
+				redraw = true
+				// U takes back the last move, D claims a draw by repetition or the 50-move rule, S saves the game,
+				// Q turns auto-queening on and off, Escape closes the promotion picker, F flips the board and
+				// the arrow keys scroll the move list
+				if (t.State == sdl.PRESSED) && (t.Keysym.Sym == sdl.K_u) && (thinking == nil) {
+					promotions = nil
+					if err := board.UndoMove(g); err == nil {
+						// taking back the last move of a finished game carries it on
+						result, ended = board.IN_PROGRESS, false
+						// against the computer, take back its answer too, or it would just play it again
+						if computer[g.PL] && !computer[1 - g.PL] && (len(g.H) > 0) {
+							board.UndoMove(g)
+						}
+						selectedPiece = nil
+						legalMoves = nil
+					}
+				}
+				if (t.State == sdl.PRESSED) && (t.Keysym.Sym == sdl.K_d) && (result == board.IN_PROGRESS) && (thinking == nil) {
+					if claim := board.ClaimableDraw(g); claim != board.IN_PROGRESS {
+						result = claim
+					}
+				}
+				if (t.State == sdl.PRESSED) && (t.Keysym.Sym == sdl.K_s) {
+					save(result)
+				}
+				if (t.State == sdl.PRESSED) && (t.Keysym.Sym == sdl.K_q) {
+					autoQueen = !autoQueen
+					if autoQueen {
+						fmt.Println("Pawns now promote to queens without asking.")
+					} else {
+						fmt.Println("Promoting pawns now asks which piece to take.")
+					}
+				}
+				if (t.State == sdl.PRESSED) && (t.Keysym.Sym == sdl.K_ESCAPE) {
+					promotions = nil
+				}
+				if (t.State == sdl.PRESSED) && (t.Keysym.Sym == sdl.K_f) {
+					flipped = !flipped
+				}
+				if (t.State == sdl.PRESSED) && (t.Keysym.Sym == sdl.K_UP) {
+					panel.Scroll(1)
+				}
+				if (t.State == sdl.PRESSED) && (t.Keysym.Sym == sdl.K_DOWN) {
+					panel.Scroll(-1)
+				}
 			}
 
 		}
 
-		if ended {
-			fmt.Println("Game Over! Player ", 1 - player, "wins!")
-			return nil
+		if (result == board.IN_PROGRESS) && computer[g.PL] && (thinking == nil) {
+			thinking = make(chan board.Move, 1)
+			go func(position *board.GameState, reply chan board.Move) {
+				m, _, _ := searcher.Search(position, limits)
+				reply <- m
+			}(g.Copy(), thinking)
+			redraw = true
+		}
+		select {
+		case move := <-thinking:
+			// only a game still going gets the computer's move
+			if result == board.IN_PROGRESS {
+				board.MakeMove(g, move)
+				result = board.Result(g)
+			}
+			thinking = nil
+			redraw = true
+		default:
 		}
 
+		if (result != board.IN_PROGRESS) && !ended {
+			// the window stays open, with the result in the side panel, until it is closed
+			if result.IsDraw() {
+				fmt.Printf("Game Over! Draw by %v.\n", result)
+			} else {
+				fmt.Printf("Game Over! %s wins by %v!\n", board.PLAYER_NAMES[1 - g.PL], result)
+			}
+			save(result)
+			ended = true
+		}
 
-		err = renderBoard(b, selectedPiece, legalMoves, window, renderer)
+		if !redraw {
+			continue
+		}
+		redraw = false
+		renderer.SetDrawColor(PANEL_BACKGROUND.R, PANEL_BACKGROUND.G, PANEL_BACKGROUND.B, 255)
+		renderer.Clear()
+		err = screen.renderBoard(g.B, selectedPiece, legalMoves, promotions, flipped)
+		if err == nil {
+			err = screen.renderCoordinates(flipped, panel.F)
+		}
+		if err == nil {
+			err = screen.renderPanel(g, result, thinking != nil, panel)
+		}
 		if err != nil {
 			fmt.Println("Board is broken:", err)
 			return err
@@ -109,8 +276,52 @@ func run() error {
 	}
 }
 
+func runPerft(fen string, depth int, showDivide bool) error {
+	g, err := board.LoadFEN(fen)
+	if err != nil {
+		fmt.Println("Board is broken:", err)
+		return err
+	}
+	start := time.Now()
+	if showDivide {
+		board.Divide(g, depth, os.Stdout)
+	} else {
+		fmt.Println("Nodes:", board.Perft(g, depth))
+	}
+	fmt.Println("Time:", time.Since(start))
+	return nil
+}
+
 func main() {
-	if err := run(); err != nil {
+	fen := flag.String("fen", board.START_FEN, "position to start from, in FEN")
+	perftDepth := flag.Int("perft", 0, "count the positions this many plies deep from -fen, then exit")
+	showDivide := flag.Bool("divide", false, "with -perft, break the count down by first move")
+	pgnPath := flag.String("pgn", "", "file to save the game to, in PGN (default chess-<date>-<time>.pgn)")
+	white := flag.String("white", "?", "name of the white player, for the saved game")
+	black := flag.String("black", "?", "name of the black player, for the saved game")
+	computerFlags := engine.AddComputerFlags(flag.CommandLine)
+	autoQueen := flag.Bool("autoqueen", false, "always promote pawns to queens, instead of asking (Q switches it in the window)")
+	fontPath := flag.String("font", "", "TrueType font for the text in the window (default a system font)")
+	flag.Parse()
+
+	computer, limits, err := computerFlags.Apply()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if computer[0] && (*white == "?") {
+		*white = "Computer"
+	}
+	if computer[1] && (*black == "?") {
+		*black = "Computer"
+	}
+
+	if *perftDepth > 0 {
+		err = runPerft(*fen, *perftDepth, *showDivide)
+	} else {
+		err = run(*fen, *pgnPath, *white, *black, computer, limits, *autoQueen, *fontPath)
+	}
+	if err != nil {
 		os.Exit(1)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"chess/board"
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+// Where to look for a font when -font isn't given: DejaVu Sans on Linux, Arial on macOS and Windows.
+var FONT_PATHS = []string{
+	"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+	"/usr/share/fonts/TTF/DejaVuSans.ttf",
+	"/usr/share/fonts/dejavu/DejaVuSans.ttf",
+	"/usr/share/fonts/dejavu-sans-fonts/DejaVuSans.ttf",
+	"/Library/Fonts/Arial.ttf",
+	"/System/Library/Fonts/Supplemental/Arial.ttf",
+	"C:\\Windows\\Fonts\\arial.ttf",
+}
+
+var PANEL_BACKGROUND = sdl.Color{R: 40, G: 44, B: 52, A: 255}
+var TEXT_COLOR = sdl.Color{R: 230, G: 230, B: 230, A: 255}
+
+// A Panel is the side panel: whose turn it is or how the game ended, the pieces each side has captured and
+// the moves so far, which scroll.
+type Panel struct{
+	F *ttf.Font       // the font, or nil if none could be opened, when there's no text      ([F]ont)
+	FP string         // the font file, or "" to look in FONT_PATHS                          ([F]ont [P]ath)
+	FS int            // the size F was opened at                                            ([F]ont [S]ize)
+	SB int            // lines the move list is scrolled back from its end                   ([S]croll [B]ack)
+
+	// The move list is only worked out again when the game changes.
+	lines []string
+	linesFor uint64   // key of the position lines were worked out for
+	linesCount int    // and the number of moves played to reach it
+}
+
+// SetFontSize opens the font again at a new size, for a new layout.
+func (p *Panel) SetFontSize(size int) {
+	if (p.F != nil) && (p.FS == size) {
+		return
+	}
+	p.Close()
+	paths := FONT_PATHS
+	if p.FP != "" {
+		paths = []string{p.FP}
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if font, err := ttf.OpenFont(path, size); err == nil {
+			p.F, p.FS = font, size
+			return
+		}
+	}
+	if p.FS == 0 {
+		// only say so once
+		fmt.Println("No font found, so the window will have no text; give one with -font.")
+		p.FS = -1
+	}
+}
+
+// Close frees the font.
+func (p *Panel) Close() {
+	if p.F != nil {
+		p.F.Close()
+		p.F = nil
+	}
+}
+
+// Scroll moves the move list back (positive) or forward (negative) by some lines.
+func (p *Panel) Scroll(lines int) {
+	p.SB += lines
+	if p.SB < 0 {
+		p.SB = 0
+	}
+}
+
+func (p *Panel) moveList(g *board.GameState) []string {
+	// The moves played, a line per move number, as 1. e4 e5 or 1... e5 if the game started with black to move.
+	key := g.Key()
+	if (p.lines != nil) && (p.linesFor == key) && (p.linesCount == len(g.H)) {
+		return p.lines
+	}
+	p.lines, p.linesFor, p.linesCount = []string{}, key, len(g.H)
+	start, err := board.LoadFEN(g.SP)
+	if err != nil {
+		return p.lines
+	}
+	number, player := start.FN, start.PL
+	for _, san := range g.PlayedSAN() {
+		if player == 0 {
+			p.lines = append(p.lines, fmt.Sprintf("%d. %s", number, san))
+		} else if len(p.lines) == 0 {
+			p.lines = append(p.lines, fmt.Sprintf("%d... %s", number, san))
+		} else {
+			p.lines[len(p.lines) - 1] += "  " + san
+		}
+		if player == 1 {
+			number += 1
+		}
+		player = 1 - player
+	}
+	return p.lines
+}
+
+func (s *Screen) drawText(font *ttf.Font, text string, x int32, y int32) error {
+	// Draw text with its top left corner at x, y; without a font, draw nothing.
+	if (font == nil) || (text == "") {
+		return nil
+	}
+	surface, err := font.RenderUTF8Blended(text, TEXT_COLOR)
+	if err != nil {
+		return err
+	}
+	defer surface.Free()
+	texture, err := s.R.CreateTextureFromSurface(surface)
+	if err != nil {
+		return err
+	}
+	defer texture.Destroy()
+	return s.R.Copy(texture, nil, &sdl.Rect{X: x, Y: y, W: surface.W, H: surface.H})
+}
+
+func textSize(font *ttf.Font, text string) (int32, int32) {
+	if font == nil {
+		return 0, 0
+	}
+	w, h, err := font.SizeUTF8(text)
+	if err != nil {
+		return 0, 0
+	}
+	return int32(w), int32(h)
+}
+
+func (s *Screen) renderCoordinates(flipped bool, font *ttf.Font) error {
+	// The files along the bottom of the board and the ranks down its left, centred on their squares.
+	for i, file := range board.FILES {
+		square := s.L.SquareRect(file, 1, flipped)
+		label := string(rune('a' + i))
+		w, _ := textSize(font, label)
+		if err := s.drawText(font, label, square.X + (square.W - w) / 2, s.L.B.Y + s.L.B.H); err != nil {
+			return err
+		}
+	}
+	for _, rank := range board.RANKS {
+		square := s.L.SquareRect('A', rank, flipped)
+		label := fmt.Sprint(rank)
+		w, h := textSize(font, label)
+		if err := s.drawText(font, label, s.L.B.X - (s.L.S / 3 + w) / 2, square.Y + (square.H - h) / 2); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func statusText(g *board.GameState, result board.GameResult, thinking bool) string {
+	// Mirrors what the terminal front end prints.
+	switch {
+	case result.IsDraw():
+		return fmt.Sprintf("Draw by %v.", result)
+	case result != board.IN_PROGRESS:
+		return fmt.Sprintf("%s wins by %v!", board.PLAYER_NAMES[1 - g.PL], result)
+	case thinking:
+		return fmt.Sprintf("%s is thinking...", board.PLAYER_NAMES[g.PL])
+	case board.CheckForCheck(g, g.PL):
+		return fmt.Sprintf("%s to move, in check", board.PLAYER_NAMES[g.PL])
+	}
+	return fmt.Sprintf("%s to move", board.PLAYER_NAMES[g.PL])
+}
+
+func capturedPieces(g *board.GameState, player int) []board.Piece {
+	// The pieces player has taken, most valuable first.
+	pieces := []board.Piece{}
+	for _, e := range g.H {
+		if (e.M.PL == player) && (e.CP != board.EMPTY_SQUARE) {
+			pieces = append(pieces, e.CP)
+		}
+	}
+	sort.Slice(pieces, func(i, j int) bool {
+		return pieces[i] & board.TYPE_MASK > pieces[j] & board.TYPE_MASK
+	})
+	return pieces
+}
+
+func (s *Screen) renderPanel(g *board.GameState, result board.GameResult, thinking bool, p *Panel) error {
+	s.R.SetDrawColor(PANEL_BACKGROUND.R, PANEL_BACKGROUND.G, PANEL_BACKGROUND.B, 255)
+	s.R.FillRect(&s.L.P)
+	pad := s.L.S / 8
+	x, y, width := s.L.P.X + pad, s.L.P.Y + pad, s.L.P.W - 2 * pad
+	lineHeight := s.L.S / 3
+	if p.F != nil {
+		lineHeight = int32(p.F.Height())
+	}
+
+	// whose turn it is, with a square of their colour, or the result
+	if result == board.IN_PROGRESS {
+		if g.PL == 0 {
+			s.R.SetDrawColor(248, 248, 248, 255)
+		} else {
+			s.R.SetDrawColor(10, 10, 10, 255)
+		}
+		s.R.FillRect(&sdl.Rect{X: x, Y: y + lineHeight / 6, W: lineHeight * 2 / 3, H: lineHeight * 2 / 3})
+		s.R.SetDrawColor(TEXT_COLOR.R, TEXT_COLOR.G, TEXT_COLOR.B, 255)
+		s.R.DrawRect(&sdl.Rect{X: x, Y: y + lineHeight / 6, W: lineHeight * 2 / 3, H: lineHeight * 2 / 3})
+	}
+	textX := x
+	if result == board.IN_PROGRESS {
+		textX += lineHeight
+	}
+	if err := s.drawText(p.F, statusText(g, result, thinking), textX, y); err != nil {
+		return err
+	}
+	y += lineHeight + pad
+
+	// the captured pieces, a row for each side, squeezed up if they don't fit
+	size := s.L.S / 2
+	for _, player := range []int{0, 1} {
+		pieces := capturedPieces(g, player)
+		step := size
+		if (len(pieces) > 1) && (int32(len(pieces)) * size > width) {
+			step = (width - size) / int32(len(pieces) - 1)
+		}
+		for i, piece := range pieces {
+			if err := s.drawPiece(piece, sdl.Rect{X: x + step * int32(i), Y: y, W: size, H: size}); err != nil {
+				return err
+			}
+		}
+		y += size
+	}
+	y += pad
+
+	// the moves, scrolled so the last ones show unless the player has scrolled back
+	lines := p.moveList(g)
+	visible := int((s.L.P.Y + s.L.P.H - pad - y) / lineHeight)
+	if visible < 1 {
+		return nil
+	}
+	if p.SB > len(lines) - visible {
+		p.SB = len(lines) - visible
+	}
+	if p.SB < 0 {
+		p.SB = 0
+	}
+	first := len(lines) - visible - p.SB
+	if first < 0 {
+		first = 0
+	}
+	for i := first; (i < len(lines)) && (i < first + visible); i++ {
+		if err := s.drawText(p.F, lines[i], x, y); err != nil {
+			return err
+		}
+		y += lineHeight
+	}
+	return nil
+}
@@ -1,54 +1,165 @@
 package main
 
 import (
+	"chess/board"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
-const SQUARE_WIDTH = 100
-const HIGHLIGHT = SQUARE_WIDTH / 3
+// The promotion picker: the pieces a pawn can become, queen first.
+var PICKER_PIECES = [4]board.Piece{board.QUEEN, board.ROOK, board.BISHOP, board.KNIGHT}
 
-const screenWidth = 8 * SQUARE_WIDTH
-const screenHeight = 8 * SQUARE_WIDTH
+func getPath(p board.Piece) string {
+	path := ""
+	switch p {
+	case board.WHITE_PAWN:
+		path = "assets/Chess_plt45.bmp"
+	case board.WHITE_KNIGHT:
+		path = "assets/Chess_nlt45.bmp"
+	case board.WHITE_BISHOP:
+		path = "assets/Chess_blt45.bmp"
+	case board.WHITE_ROOK:
+		path = "assets/Chess_rlt45.bmp"
+	case board.WHITE_QUEEN:
+		path = "assets/Chess_qlt45.bmp"
+	case board.WHITE_KING:
+		path = "assets/Chess_klt45.bmp"
+	case board.BLACK_PAWN:
+		path = "assets/Chess_pdt45.bmp"
+	case board.BLACK_KNIGHT:
+		path = "assets/Chess_ndt45.bmp"
+	case board.BLACK_BISHOP:
+		path = "assets/Chess_bdt45.bmp"
+	case board.BLACK_ROOK:
+		path = "assets/Chess_rdt45.bmp"
+	case board.BLACK_QUEEN:
+		path = "assets/Chess_qdt45.bmp"
+	case board.BLACK_KING:
+		path = "assets/Chess_kdt45.bmp"
+	}
+
+	return path
+}
+
+// A Screen draws the game in the window. The piece images are loaded into textures once, when it's made,
+// rather than every time a piece is drawn.
+type Screen struct{
+	R *sdl.Renderer              // what it draws with                           ([R]enderer)
+	L Layout                     // where everything goes                        ([L]ayout)
+	T [2][7]*sdl.Texture         // the piece images, by player and piece type   ([T]extures)
+}
+
+// NewScreen loads the piece images for drawing with r.
+func NewScreen(r *sdl.Renderer, l Layout) (*Screen, error) {
+	s := &Screen{R: r, L: l}
+	for player := 0; player < 2; player++ {
+		for kind := board.PAWN; kind <= board.KING; kind++ {
+			p := kind
+			if player == 1 {
+				p |= board.BLACK
+			}
+			img, err := sdl.LoadBMP(getPath(p))
+			if err != nil {
+				s.Destroy()
+				return nil, err
+			}
+			s.T[player][kind], err = r.CreateTextureFromSurface(img)
+			img.Free()
+			if err != nil {
+				s.Destroy()
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
 
+// Destroy frees the textures.
+func (s *Screen) Destroy() {
+	for player := range s.T {
+		for kind, texture := range s.T[player] {
+			if texture != nil {
+				texture.Destroy()
+				s.T[player][kind] = nil
+			}
+		}
+	}
+}
 
+func (s *Screen) drawPiece(p board.Piece, dst sdl.Rect) error {
+	// Draw p's image scaled into dst.
+	if p == board.EMPTY_SQUARE {
+		return nil
+	}
+	return s.R.Copy(s.T[board.PlayerOf(p)][p & board.TYPE_MASK], &sdl.Rect{X: 0, Y: 0, W: 141, H: 141}, &dst)
+}
 
-func renderBoard(b Board, selectedPiece []int, highlightedSquares MoveSequence, w *sdl.Window, r *sdl.Renderer) error {
-	for i, file := range FILES {
-		for j, rank := range RANKS {
-			if (i + j) % 2 == 0 {
-				r.SetDrawColor(248, 231, 187, 255)
+func (s *Screen) renderBoard(b board.Board, selectedPiece []int, highlightedSquares board.MoveSequence, promotions board.MoveSequence, flipped bool) error {
+	for i, file := range board.FILES {
+		for j, rank := range board.RANKS {
+			square := s.L.SquareRect(file, rank, flipped)
+			// a1 is a dark square
+			if (i + j) % 2 == 1 {
+				s.R.SetDrawColor(248, 231, 187, 255)
 			} else {
-				r.SetDrawColor(0, 68, 116, 255)
+				s.R.SetDrawColor(0, 68, 116, 255)
 			}
 			if (selectedPiece != nil) && (file == selectedPiece[0]) && (rank == selectedPiece[1]) {
-				r.SetDrawColor(19, 196, 163, 255)
+				s.R.SetDrawColor(19, 196, 163, 255)
 			}
-			r.FillRect(&sdl.Rect{int32(SQUARE_WIDTH * i), int32(SQUARE_WIDTH * j), int32(SQUARE_WIDTH), int32(SQUARE_WIDTH)})
-
-			path := getPath(b[file][rank])
-
-			if path != "" {	
-				img, err := sdl.LoadBMP(path)
-				if err != nil {
-					return err
-				}
-				defer img.Free()
-				pieceTex, err := r.CreateTextureFromSurface(img)
-				if err != nil {
-					return err
-				}
-				defer pieceTex.Destroy()
-				r.Copy(pieceTex, &sdl.Rect{0, 0, 141, 141}, &sdl.Rect{int32(SQUARE_WIDTH * i), int32(SQUARE_WIDTH * j), int32(SQUARE_WIDTH), int32(SQUARE_WIDTH)})
+			s.R.FillRect(&square)
+			if err := s.drawPiece(b.At(file, rank), square); err != nil {
+				return err
 			}
 		}
 	}
 	if highlightedSquares != nil {
-		r.SetDrawColor(119, 136, 153, 255)
+		s.R.SetDrawColor(119, 136, 153, 255)
 		for _, move := range highlightedSquares {
-			i := move.DF - 'A'
-			j := move.DR - 1
-			r.FillRect(&sdl.Rect{int32(SQUARE_WIDTH * i + HIGHLIGHT), int32(SQUARE_WIDTH * j + HIGHLIGHT), int32(HIGHLIGHT), int32(HIGHLIGHT)})
+			square := s.L.SquareRect(move.DF, move.DR, flipped)
+			s.R.FillRect(&sdl.Rect{X: square.X + s.L.S / 3, Y: square.Y + s.L.S / 3, W: s.L.S / 3, H: s.L.S / 3})
+		}
+	}
+	if promotions != nil {
+		return s.renderPromotionPicker(promotions)
+	}
+	return nil
+}
+
+func (s *Screen) renderPromotionPicker(promotions board.MoveSequence) error {
+	// Dim the board and show the pieces the pawn can become over it.
+	s.R.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	s.R.SetDrawColor(0, 0, 0, 160)
+	s.R.FillRect(&s.L.B)
+	s.R.SetDrawBlendMode(sdl.BLENDMODE_NONE)
+	for i, kind := range PICKER_PIECES {
+		rect := s.L.PickerRect(i)
+		s.R.SetDrawColor(248, 231, 187, 255)
+		s.R.FillRect(&rect)
+		s.R.SetDrawColor(19, 196, 163, 255)
+		s.R.DrawRect(&rect)
+		// the moving pawn's colour
+		if err := s.drawPiece(kind | (promotions[0].P &^ board.TYPE_MASK), rect); err != nil {
+			return err
 		}
 	}
 	return nil
+}
+
+func promotionAt(promotions board.MoveSequence, l Layout, x int32, y int32) (board.Move, bool) {
+	// The promotion whose piece in the picker is at x, y, if any.
+	for i, kind := range PICKER_PIECES {
+		if inRect(l.PickerRect(i), x, y) {
+			return promotionTo(promotions, kind)
+		}
+	}
+	return board.Move{}, false
+}
+
+func promotionTo(promotions board.MoveSequence, kind board.Piece) (board.Move, bool) {
+	for _, move := range promotions {
+		if move.P & board.TYPE_MASK == kind {
+			return move, true
+		}
+	}
+	return board.Move{}, false
 }
\ No newline at end of file
@@ -0,0 +1,271 @@
+// Package uci runs the engine over the Universal Chess Interface
+// (https://www.shredderchess.com/chess-features/uci-universal-chess-interface.html), so that chess GUIs
+// and tournament managers such as cutechess-cli can play it.
+package uci
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chess/board"
+	"chess/engine"
+)
+
+const ENGINE_NAME = "chess"
+const ENGINE_AUTHOR = "the chess contributors"
+
+// A Session is the engine's side of one UCI conversation.
+type Session struct{
+	G *board.GameState    // the position from the last position command ([G]ame)
+	S *engine.Searcher    // the engine                                   ([S]earcher)
+	MO time.Duration      // time held back from each move for lag        ([M]ove [O]verhead)
+
+	out io.Writer
+	lock sync.Mutex       // guards out, which the search writes info lines to as it goes
+	searching bool
+	done chan struct{}    // closed when the search in progress has sent its bestmove
+	stop chan struct{}    // closed by stop, so an infinite search knows it may send its bestmove
+}
+
+// Run answers the UCI commands read from in, writing the replies to out, until quit or the end of in.
+func Run(in io.Reader, out io.Writer) error {
+	g, _ := board.NewGame()
+	s := &Session{G: g, S: engine.NewSearcher(), MO: 30 * time.Millisecond, out: out}
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" {
+			s.stopSearch()
+			return nil
+		}
+		s.handle(fields)
+	}
+	s.stopSearch()
+	return scanner.Err()
+}
+
+func (s *Session) send(format string, args ...interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	fmt.Fprintf(s.out, format + "\n", args...)
+}
+
+func (s *Session) handle(fields []string) {
+	switch fields[0] {
+	case "uci":
+		s.send("id name %s", ENGINE_NAME)
+		s.send("id author %s", ENGINE_AUTHOR)
+		s.send("option name Hash type spin default %d min 1 max 1024", engine.DEFAULT_HASH_MB)
+		s.send("option name Move Overhead type spin default 30 min 0 max 5000")
+		s.send("option name EvalFile type string default <empty>")
+		s.send("uciok")
+	case "isready":
+		s.send("readyok")
+	case "ucinewgame":
+		s.stopSearch()
+		s.G, _ = board.NewGame()
+		s.S.Clear()
+	case "position":
+		s.stopSearch()
+		if err := s.position(fields[1:]); err != nil {
+			s.send("info string %v", err)
+		}
+	case "go":
+		s.stopSearch()
+		s.goSearch(fields[1:])
+	case "stop":
+		s.stopSearch()
+	case "setoption":
+		s.setOption(fields[1:])
+	case "debug", "ponderhit", "register":
+		// nothing to do: no debug output, no pondering and no registration
+	default:
+		s.send("info string Unknown command %q.", strings.Join(fields, " "))
+	}
+}
+
+func (s *Session) position(args []string) error {
+	// position startpos [moves M1 M2 ...] or position fen FEN [moves M1 M2 ...]
+	if len(args) == 0 {
+		return fmt.Errorf("Position needs startpos or fen.")
+	}
+	fen := board.START_FEN
+	moves := []string{}
+	rest := args[1:]
+	for i, arg := range rest {
+		if arg == "moves" {
+			moves = rest[i + 1:]
+			rest = rest[:i]
+			break
+		}
+	}
+	switch args[0] {
+	case "startpos":
+	case "fen":
+		fen = strings.Join(rest, " ")
+	default:
+		return fmt.Errorf("Unknown position %q.", args[0])
+	}
+	g, err := board.LoadFEN(fen)
+	if err != nil {
+		return err
+	}
+	for _, text := range moves {
+		m, err := board.ParseMove(g, text)
+		if err != nil {
+			return err
+		}
+		board.MakeMove(g, m)
+	}
+	s.G = g
+	return nil
+}
+
+func (s *Session) goSearch(args []string) {
+	// go [depth D] [nodes N] [movetime MS] [wtime MS] [btime MS] [winc MS] [binc MS] [movestogo N] [infinite]
+	limits := engine.Limits{}
+	infinite := false
+	for i := 0; i < len(args); i++ {
+		n := 0
+		if i + 1 < len(args) {
+			n, _ = strconv.Atoi(args[i + 1])
+		}
+		ms := time.Duration(n) * time.Millisecond
+		switch args[i] {
+		case "infinite", "ponder":
+			infinite = true
+			continue
+		case "depth":
+			limits.D = n
+		case "nodes":
+			limits.N = n
+		case "movetime":
+			limits.MT = s.lessOverhead(ms)
+		case "wtime":
+			limits.T[0] = s.lessOverhead(ms)
+		case "btime":
+			limits.T[1] = s.lessOverhead(ms)
+		case "winc":
+			limits.I[0] = ms
+		case "binc":
+			limits.I[1] = ms
+		case "movestogo":
+			limits.MTG = n
+		default:
+			continue
+		}
+		i += 1
+	}
+
+	g := s.G.Copy()
+	s.S.OnInfo = func(info engine.Info) {
+		s.sendInfo(g, info)
+	}
+	s.S.Reset()
+	s.searching = true
+	s.done = make(chan struct{})
+	s.stop = make(chan struct{})
+	go func(done chan struct{}, stop chan struct{}) {
+		defer close(done)
+		m, _, ok := s.S.Search(g, limits)
+		if infinite {
+			// an infinite search may only answer once it is told to stop
+			<-stop
+		}
+		if !ok {
+			s.send("bestmove 0000")
+			return
+		}
+		s.send("bestmove %s", board.CoordinateNotation(g, m))
+	}(s.done, s.stop)
+}
+
+func (s *Session) lessOverhead(t time.Duration) time.Duration {
+	if t - s.MO < time.Millisecond {
+		return time.Millisecond
+	}
+	return t - s.MO
+}
+
+func (s *Session) stopSearch() {
+	// End the search in progress, if there is one, and wait for its bestmove.
+	if !s.searching {
+		return
+	}
+	s.S.Stop()
+	close(s.stop)
+	<-s.done
+	s.searching = false
+}
+
+func (s *Session) sendInfo(g *board.GameState, info engine.Info) {
+	score := fmt.Sprintf("cp %d", info.S)
+	if n, mate := info.MateIn(); mate {
+		score = fmt.Sprintf("mate %d", n)
+	}
+	nps := 0
+	if ms := info.T.Milliseconds(); ms > 0 {
+		nps = int(int64(info.N) * 1000 / ms)
+	}
+	pv := make([]string, 0, len(info.PV))
+	tempG := g.Copy()
+	for _, m := range info.PV {
+		pv = append(pv, board.CoordinateNotation(tempG, m))
+		board.PlayMove(tempG, m)
+	}
+	s.send("info depth %d score %s nodes %d nps %d time %d pv %s", info.D, score, info.N, nps,
+		info.T.Milliseconds(), strings.Join(pv, " "))
+}
+
+func (s *Session) setOption(args []string) {
+	// setoption name NAME [value VALUE], where both may have spaces in them
+	text := strings.Join(args, " ")
+	if !strings.HasPrefix(text, "name ") {
+		s.send("info string Setoption needs a name.")
+		return
+	}
+	name, value := strings.TrimPrefix(text, "name "), ""
+	if i := strings.Index(name, " value "); i >= 0 {
+		name, value = name[:i], name[i + len(" value "):]
+	}
+	switch strings.ToLower(name) {
+	case "hash":
+		mb, err := strconv.Atoi(value)
+		if (err != nil) || (mb < 1) || (mb > 1024) {
+			s.send("info string Bad Hash %q.", value)
+			return
+		}
+		s.stopSearch()
+		s.S.TT = engine.NewTranspositionTable(mb)
+	case "move overhead":
+		ms, err := strconv.Atoi(value)
+		if (err != nil) || (ms < 0) || (ms > 5000) {
+			s.send("info string Bad Move Overhead %q.", value)
+			return
+		}
+		s.MO = time.Duration(ms) * time.Millisecond
+	case "evalfile":
+		// the evaluation weights, as written by the tuner; <empty> goes back to the built-in ones
+		s.stopSearch()
+		if (value == "") || (value == "<empty>") {
+			engine.PARAMS = &engine.DEFAULT_PARAMS
+			return
+		}
+		params, err := engine.LoadParams(value)
+		if err != nil {
+			s.send("info string %v", err)
+			return
+		}
+		engine.PARAMS = params
+	default:
+		s.send("info string Unknown option %q.", name)
+	}
+}
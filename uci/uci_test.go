@@ -0,0 +1,130 @@
+package uci
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// an engine running in the background, talked to the way a GUI would
+type conversation struct{
+	t *testing.T
+	in *io.PipeWriter
+	lines chan string
+}
+
+func start(t *testing.T) *conversation {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &conversation{t: t, in: inW, lines: make(chan string, 1000)}
+	go func() {
+		Run(inR, outW)
+		outW.Close()
+	}()
+	go func() {
+		scanner := bufio.NewScanner(outR)
+		for scanner.Scan() {
+			c.lines <- scanner.Text()
+		}
+		close(c.lines)
+	}()
+	return c
+}
+
+func (c *conversation) send(line string) {
+	io.WriteString(c.in, line + "\n")
+}
+
+func (c *conversation) expect(prefix string) []string {
+	// the lines up to and including the first that starts with prefix
+	c.t.Helper()
+	seen := []string{}
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case line, ok := <-c.lines:
+			if !ok {
+				c.t.Fatalf("engine stopped before %q; it said %q", prefix, seen)
+			}
+			seen = append(seen, line)
+			if strings.HasPrefix(line, prefix) {
+				return seen
+			}
+		case <-timeout:
+			c.t.Fatalf("no %q after 10s; engine said %q", prefix, seen)
+		}
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	c := start(t)
+	c.send("uci")
+	lines := c.expect("uciok")
+	if !strings.HasPrefix(lines[0], "id name ") {
+		t.Errorf("uci answered %q", lines)
+	}
+	if !strings.Contains(strings.Join(lines, "\n"), "option name Hash type spin") {
+		t.Errorf("no Hash option in %q", lines)
+	}
+	c.send("setoption name Move Overhead value 50")
+	c.send("setoption name Hash value 8")
+	c.send("setoption name Hash value 0")
+	c.expect("info string Bad Hash \"0\".")
+	c.send("setoption name EvalFile value /nonexistent/params.txt")
+	c.expect("info string open /nonexistent/params.txt")
+	c.send("setoption name EvalFile value <empty>")
+	c.send("setoption name Nonsense value 1")
+	c.expect("info string Unknown option \"Nonsense\".")
+	c.send("isready")
+	c.expect("readyok")
+	c.send("quit")
+}
+
+func TestGo(t *testing.T) {
+	c := start(t)
+	// white mates with Qxf7#
+	c.send("position startpos moves e2e4 e7e5 d1h5 b8c6 f1c4 g8f6")
+	c.send("go depth 2")
+	lines := c.expect("bestmove")
+	if lines[len(lines) - 1] != "bestmove h5f7" {
+		t.Errorf("search said %q", lines)
+	}
+	if !strings.HasPrefix(lines[0], "info depth 1 score ") || !strings.Contains(lines[len(lines) - 2], "score mate 1") {
+		t.Errorf("info lines %q", lines)
+	}
+
+	c.send("ucinewgame")
+	c.send("position fen 7k/8/8/8/8/8/8/K7 w - - 0 1 moves a1b1")
+	c.send("go wtime 1000 btime 1000 winc 10 binc 10")
+	if lines := c.expect("bestmove"); !strings.HasPrefix(lines[len(lines) - 1], "bestmove h8") {
+		t.Errorf("search said %q", lines)
+	}
+
+	c.send("position fen 7k/6Q1/5K2/8/8/8/8/8 b - - 0 1")
+	c.send("go movetime 100")
+	c.expect("bestmove 0000")
+
+	c.send("position startpos")
+	c.send("go infinite")
+	time.Sleep(50 * time.Millisecond)
+	for waiting := true; waiting; {
+		select {
+		case line := <-c.lines:
+			if !strings.HasPrefix(line, "info") {
+				t.Errorf("infinite search answered %q before stop", line)
+			}
+		default:
+			waiting = false
+		}
+	}
+	c.send("stop")
+	c.expect("bestmove")
+
+	// a stop straight after go still ends the search
+	c.send("go infinite")
+	c.send("stop")
+	c.expect("bestmove")
+	c.send("quit")
+}